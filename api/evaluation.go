@@ -0,0 +1,24 @@
+package api
+
+// EvaluationContext describes the subject a feature is evaluated for. Unlike
+// the actors and groups query params, values are never split on commas. The IP
+// defaults to the caller's IP.
+type EvaluationContext struct {
+	Actor      string            `json:"actor,omitempty"`
+	Groups     []string          `json:"groups,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	IP         string            `json:"ip,omitempty"`
+}
+
+// EvaluationRequest is used to get the state of a single feature.
+type EvaluationRequest struct {
+	Context EvaluationContext `json:"context"`
+}
+
+// BatchEvaluationRequest is used to get the state of a collection of features
+// for a single evaluation context.
+type BatchEvaluationRequest struct {
+	Context            EvaluationContext  `json:"context"`
+	NamespacedFeatures NamespacedFeatures `json:"namespacedFeatures,omitempty"`
+	Features           []string           `json:"features,omitempty"`
+}
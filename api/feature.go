@@ -11,3 +11,10 @@ type GetFeatureListResponse struct {
 type FeatureResponse struct {
 	Feature *model.Feature `json:"feature"`
 }
+
+// PromoteFeatureRequest is used to copy a feature's gate settings from one
+// environment to another.
+type PromoteFeatureRequest struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
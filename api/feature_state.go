@@ -5,11 +5,33 @@ package api
 // to that namespace.
 type NamespacedFeatures map[string][]string
 
-// FeatureState represents an individual feature's gate state.
+// FeatureState represents an individual feature's gate state, along with the
+// value served for it, decoded to the feature's type: the feature's value if
+// enabled, or its off value if disabled. Enabled multivariate features include
+// the assigned variant and its value instead. The reason is only included when
+// requested in debug mode.
 type FeatureState struct {
-	Namespace string `json:"namespace,omitempty"`
-	Key       string `json:"key"`
-	Enabled   bool   `json:"enabled"`
+	Namespace string            `json:"namespace,omitempty"`
+	Key       string            `json:"key"`
+	Enabled   bool              `json:"enabled"`
+	Variant   string            `json:"variant,omitempty"`
+	Value     interface{}       `json:"value,omitempty"`
+	Reason    *EvaluationReason `json:"reason,omitempty"`
+}
+
+// EvaluationReason explains a feature state: which gate type matched and on
+// which actor, group, segment or CIDR range, the actor's bucket for percentage gate types,
+// the deny list entry or the unmet prerequisite. A "noMatch" kind means that no
+// gate type enabled the feature.
+type EvaluationReason struct {
+	Kind         string  `json:"kind"`
+	GateType     string  `json:"gateType,omitempty"`
+	Actor        string  `json:"actor,omitempty"`
+	Group        string  `json:"group,omitempty"`
+	Segment      string  `json:"segment,omitempty"`
+	CIDR         string  `json:"cidr,omitempty"`
+	Bucket       *uint32 `json:"bucket,omitempty"`
+	Prerequisite string  `json:"prerequisite,omitempty"`
 }
 
 // BatchFeatureState presents a collection of FeatureStates.
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"time"
+
+	"github.com/robzienert/lever/model"
+)
+
+// StaleFeature is a feature that is due to be removed. Overdue features are
+// past their removal date, and rolled out features have been enabled for
+// everyone since RolledOutSince.
+type StaleFeature struct {
+	Namespace      string           `json:"namespace,omitempty"`
+	Key            string           `json:"key"`
+	Lifecycle      *model.Lifecycle `json:"lifecycle,omitempty"`
+	Overdue        bool             `json:"overdue"`
+	RolledOutSince *time.Time       `json:"rolledOutSince,omitempty"`
+}
+
+// StaleFeatureReport is the HTTP response wrapper for stale features.
+type StaleFeatureReport struct {
+	Features []StaleFeature `json:"features"`
+}
@@ -0,0 +1,13 @@
+package api
+
+import "github.com/robzienert/lever/model"
+
+// GetSegmentListResponse is the HTTP response wrapper for segment lists.
+type GetSegmentListResponse struct {
+	Segments []*model.Segment `json:"segments"`
+}
+
+// SegmentResponse is the HTTP response wrapper for a single segment.
+type SegmentResponse struct {
+	Segment *model.Segment `json:"segment"`
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"time"
+
+	"github.com/robzienert/lever/model"
+)
+
+// FeatureUsage summarizes the evaluations of a feature, with the usage of each
+// OAuth client and outcome.
+type FeatureUsage struct {
+	Namespace     string         `json:"namespace,omitempty"`
+	Key           string         `json:"key"`
+	Evaluations   int64          `json:"evaluations"`
+	LastEvaluated *time.Time     `json:"lastEvaluated,omitempty"`
+	Usage         []*model.Usage `json:"usage"`
+}
+
+// GetUsageResponse is the HTTP response wrapper for feature usage.
+type GetUsageResponse struct {
+	Features []FeatureUsage `json:"features"`
+}
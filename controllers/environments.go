@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/session"
+	"github.com/robzienert/lever/store"
+)
+
+// PromoteFeature copies a feature's gate settings from one environment to
+// another, replacing the settings of the target environment.
+func PromoteFeature(c *gin.Context) {
+	var in api.PromoteFeatureRequest
+	if err := c.BindJSON(&in); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	feature, err := store.GetFeature(c, c.Query(namespaceQuery), c.Param(keyParam))
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if feature == nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	if !ifMatch(c, feature) {
+		c.AbortWithError(http.StatusPreconditionFailed, errPreconditionFailed)
+		return
+	}
+
+	next := feature.Copy()
+	if err = next.Promote(in.From, in.To); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	next.LastUpdated = time.Now().UTC()
+
+	if err = store.UpsertFeature(c, next); err != nil {
+		c.AbortWithError(writeStatus(c, err), err)
+		return
+	}
+
+	fields := feature.Diff(next)
+	fields["key"] = feature.Key
+	fields["ns"] = feature.Namespace
+	fields["from"] = in.From
+	fields["to"] = in.To
+	breadcrumb := model.NewBreadcrumb("promote feature", session.AuditActor(c)).WithFields(fields)
+
+	go saveBreadcrumb(c.Copy(), breadcrumb)
+
+	setFeatureETag(c, next)
+	c.IndentedJSON(http.StatusOK, api.FeatureResponse{Feature: next})
+}
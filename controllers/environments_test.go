@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/context"
+	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvironmentsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EnvironmentsTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *EnvironmentsTestSuite) servePromote(s store.Store, body string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(context.SetStore(s))
+	router.POST("/features/:key/promote", PromoteFeature)
+
+	req, _ := http.NewRequest("POST", "/features/foo/promote", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func (suite *EnvironmentsTestSuite) TestPromote_BadRequest() {
+	resp := suite.servePromote(memory.Load(), `{"from":"staging"}`)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *EnvironmentsTestSuite) TestPromote_NotFound() {
+	resp := suite.servePromote(memory.Load(), `{"from":"staging","to":"prod"}`)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)
+}
+
+func (suite *EnvironmentsTestSuite) TestPromote_NoSettings() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{}})
+
+	resp := suite.servePromote(memStore, `{"from":"staging","to":"prod"}`)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *EnvironmentsTestSuite) TestPromote_OK() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{
+		Key:  "foo",
+		Gate: &model.Gate{Value: "false", Seed: "foo"},
+		Environments: map[string]*model.Gate{
+			"staging": {Groups: []string{"beta"}},
+			"prod":    {Value: "false"},
+		},
+	})
+
+	resp := suite.servePromote(memStore, `{"from":"staging","to":"prod"}`)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	featureResp := &api.FeatureResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), featureResp))
+	assert.Equal(suite.T(), &model.Gate{Groups: []string{"beta"}, Seed: "foo"}, featureResp.Feature.Environments["prod"])
+
+	stored, _ := memStore.Features().Get("foo")
+	assert.Equal(suite.T(), []string{"beta"}, stored.Environments["prod"].Groups)
+	assert.Equal(suite.T(), &model.Gate{Groups: []string{"beta"}}, stored.Environments["staging"])
+
+	// Breadcrumbs are saved asynchronously.
+	time.Sleep(50 * time.Millisecond)
+	breadcrumbs, _ := memStore.Breadcrumbs().GetList()
+	if assert.Len(suite.T(), breadcrumbs, 1) {
+		b := breadcrumbs[0]
+		assert.Equal(suite.T(), "promote feature", b.Action)
+		assert.Equal(suite.T(), "staging", b.Fields["from"])
+		assert.Equal(suite.T(), "prod", b.Fields["to"])
+		assert.Equal(suite.T(), `{"value":"false"} -> {"groups":["beta"],"seed":"foo"}`, b.Fields["environment_prod"])
+	}
+}
+
+func (suite *EnvironmentsTestSuite) TestPromote_StaleIfMatch() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{
+		Key:          "foo",
+		Gate:         &model.Gate{},
+		Environments: map[string]*model.Gate{"staging": {Groups: []string{"beta"}}},
+	})
+	router := gin.New()
+	router.Use(context.SetStore(memStore))
+	router.POST("/features/:key/promote", PromoteFeature)
+
+	cases := []struct {
+		ifMatch string
+		code    int
+	}{
+		{`"0"`, http.StatusPreconditionFailed},
+		{`"1"`, http.StatusOK},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("POST", "/features/foo/promote", strings.NewReader(`{"from":"staging","to":"prod"}`))
+		req.Header.Set(ifMatchHeader, c.ifMatch)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(suite.T(), c.code, resp.Code, c.ifMatch)
+	}
+}
+
+func TestEnvironmentsTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvironmentsTestSuite))
+}
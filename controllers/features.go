@@ -2,26 +2,41 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/gin-gonic/gin"
 	"github.com/robzienert/gin-middleware/correlationid"
 	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/exposure"
 	"github.com/robzienert/lever/metrics"
 	"github.com/robzienert/lever/model"
 	"github.com/robzienert/lever/processor"
+	"github.com/robzienert/lever/router/middleware/clientip"
 	"github.com/robzienert/lever/router/middleware/session"
 	"github.com/robzienert/lever/shared/strutil"
 	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/usage"
 )
 
 const (
-	namespaceQuery = "ns"
-	actorsQuery    = "actors"
-	groupsQuery    = "groups"
-	keyParam       = "key"
+	namespaceQuery       = "ns"
+	actorsQuery          = "actors"
+	groupsQuery          = "groups"
+	forceQuery           = "force"
+	debugQuery           = "debug"
+	envQuery             = "env"
+	ipQuery              = "ip"
+	attributeQueryPrefix = "attr."
+	keyParam             = "key"
+
+	// overdueHeader lists the IDs of evaluated features that are past their
+	// removal date.
+	overdueHeader = "X-Lever-Overdue"
 )
 
 // GetAllFeatures returns all features and their gate settings.
@@ -29,6 +44,9 @@ const (
 // Allows passing the "ns" query param to get all features just by namespace.
 // Not passing the ns query param will return only those features without a
 // namespace.
+//
+// Feature reads accept an "env" query param, which returns each feature with
+// the environment's gate settings as its gate.
 func GetAllFeatures(c *gin.Context) {
 	features, err := store.GetFeatureList(c, c.Query(namespaceQuery))
 	if err != nil {
@@ -38,6 +56,11 @@ func GetAllFeatures(c *gin.Context) {
 	if features == nil {
 		features = make([]*model.Feature, 0)
 	}
+	if env := c.Query(envQuery); env != "" {
+		for i, f := range features {
+			features[i] = f.InEnvironment(env)
+		}
+	}
 
 	c.IndentedJSON(http.StatusOK, api.GetFeatureListResponse{Features: features})
 }
@@ -53,11 +76,19 @@ func GetFeature(c *gin.Context) {
 		c.AbortWithStatus(http.StatusNotFound)
 		return
 	}
+	if env := c.Query(envQuery); env != "" {
+		feature = feature.InEnvironment(env)
+	}
 
+	setFeatureETag(c, feature)
 	c.IndentedJSON(http.StatusOK, api.FeatureResponse{Feature: feature})
 }
 
 // PutFeature idemopotently upserts a feature and its associated gates.
+//
+// Feature reads and writes return the feature's revision as an ETag. Writes and
+// deletes with an If-Match header only succeed if it matches the revision that
+// is changed.
 func PutFeature(c *gin.Context) {
 	var in model.Feature
 	if err := c.BindJSON(&in); err != nil {
@@ -70,41 +101,129 @@ func PutFeature(c *gin.Context) {
 		return
 	}
 
+	if err := in.Validate(); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if len(in.Prerequisites) > 0 {
+		if status, err := checkPrerequisites(c, &in); err != nil {
+			c.AbortWithError(status, err)
+			return
+		}
+	}
+	for _, name := range in.SegmentNames() {
+		segment, err := store.GetSegment(c, name)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		if segment == nil {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("segment does not exist: %s", name))
+			return
+		}
+	}
+
 	feature, err := store.GetFeature(c, in.Namespace, in.Key)
 	if err != nil {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
+	if !ifMatch(c, feature) {
+		c.AbortWithError(http.StatusPreconditionFailed, errPreconditionFailed)
+		return
+	}
 
 	var breadcrumb *model.Breadcrumb
 	now := time.Now().UTC()
 	if feature == nil {
 		feature = &in
 		feature.DateCreated = now
+		feature.Revision = 0
+		if feature.Gate.Seed == "" {
+			feature.Gate.Seed = feature.BucketSeed()
+		}
+		if feature.Rollout != nil {
+			feature.Rollout.Start(now)
+			feature.Gate.ActorPercent = feature.Rollout.Percent()
+		}
 
-		breadcrumb = model.NewBreadcrumb("create feature", session.AuditActor(c))
+		breadcrumb = model.NewBreadcrumb(model.CreateFeatureAction, session.AuditActor(c))
 	} else {
+		// Features that predate seeds keep their unsalted buckets until a seed
+		// is explicitly set.
+		if in.Gate.Seed == "" {
+			in.Gate.Seed = feature.Gate.Seed
+		}
+		// A new or changed rollout plan starts from its first step, while an
+		// unchanged plan keeps its progress and its control of the actor
+		// percent.
+		if in.Rollout != nil {
+			if in.Rollout.SamePlan(feature.Rollout) {
+				in.Rollout = feature.Rollout
+			} else {
+				in.Rollout.Start(now)
+			}
+			if in.Rollout.Active() {
+				in.Gate.ActorPercent = in.Rollout.Percent()
+			}
+		}
 		diff := feature.Diff(&in)
 
 		feature.Type = in.Type
 		feature.Value = in.Value
+		feature.OffValue = in.OffValue
+		feature.Schema = in.Schema
 		feature.Gate = in.Gate
+		feature.Environments = in.Environments
+		feature.Variants = in.Variants
+		feature.Prerequisites = in.Prerequisites
+		feature.Rollout = in.Rollout
+		feature.Lifecycle = in.Lifecycle
 
-		breadcrumb = model.NewBreadcrumb("update feature", session.AuditActor(c)).WithFields(diff)
+		breadcrumb = model.NewBreadcrumb(model.UpdateFeatureAction, session.AuditActor(c)).WithFields(diff)
 	}
 	feature.LastUpdated = now
+	breadcrumb.WithField("key", feature.Key).WithField("ns", feature.Namespace)
 
 	if err = store.UpsertFeature(c, feature); err != nil {
-		c.AbortWithError(http.StatusInternalServerError, err)
+		c.AbortWithError(writeStatus(c, err), err)
 		return
 	}
 
 	go saveBreadcrumb(c.Copy(), breadcrumb)
 
+	setFeatureETag(c, feature)
 	c.IndentedJSON(http.StatusOK, api.FeatureResponse{Feature: feature})
 }
 
-// DeleteFeature removes a feature from the service.
+// checkPrerequisites ensures that every prerequisite of the feature exists and
+// that none of them depend back on the feature.
+func checkPrerequisites(c *gin.Context, feature *model.Feature) (int, error) {
+	lookup := featureLookup(c)
+	for _, p := range feature.Prerequisites {
+		f, err := lookup(p.Namespace, p.Key)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if f == nil {
+			return http.StatusBadRequest, fmt.Errorf("prerequisite feature does not exist: %s", p.ID())
+		}
+	}
+
+	cycle, err := feature.PrerequisiteCycle(lookup)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if cycle != nil {
+		return http.StatusBadRequest, fmt.Errorf("prerequisite cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return http.StatusOK, nil
+}
+
+// DeleteFeature removes a feature from the service. Features that other
+// features depend on as a prerequisite are only removed when the "force" query
+// param is "true".
 func DeleteFeature(c *gin.Context) {
 	feature, err := store.GetFeature(c, c.Query(namespaceQuery), c.Param(keyParam))
 	if err != nil {
@@ -115,16 +234,34 @@ func DeleteFeature(c *gin.Context) {
 		c.AbortWithStatus(http.StatusNotFound)
 		return
 	}
+	if !ifMatch(c, feature) {
+		c.AbortWithError(http.StatusPreconditionFailed, errPreconditionFailed)
+		return
+	}
 
-	if err = store.DeleteFeature(c, feature); err != nil {
+	dependents, err := dependentFeatures(c, feature)
+	if err != nil {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
+	if len(dependents) > 0 && c.Query(forceQuery) != "true" {
+		c.AbortWithError(http.StatusConflict, fmt.Errorf("features depend on %s: %s", feature.ID(), strings.Join(dependents, ", ")))
+		return
+	}
+
+	if err = store.DeleteFeature(c, feature); err != nil {
+		c.AbortWithError(writeStatus(c, err), err)
+		return
+	}
 
-	breadcrumb := model.NewBreadcrumb("delete feature", session.AuditActor(c)).WithFields(model.Fields{
+	fields := model.Fields{
 		"key": feature.Key,
 		"ns":  feature.Namespace,
-	})
+	}
+	if len(dependents) > 0 {
+		fields["dependents"] = strings.Join(dependents, ",")
+	}
+	breadcrumb := model.NewBreadcrumb("delete feature", session.AuditActor(c)).WithFields(fields)
 
 	go saveBreadcrumb(c.Copy(), breadcrumb)
 
@@ -132,39 +269,34 @@ func DeleteFeature(c *gin.Context) {
 }
 
 // GetFeatureState will retrieve the current gate state of a feature, given
-// optional actors and groups.
+// optional actors, groups and attributes. Attributes are passed as query params
+// prefixed with "attr.", such as "attr.country=DE". The IP evaluated by CIDR
+// gates is the caller's, unless passed as the "ip" query param.
+//
+// All state endpoints accept a "debug" query param. When it is "true", each
+// state includes the reason for its evaluation. They also accept an "env"
+// query param to evaluate features with the environment's gate settings.
 func GetFeatureState(c *gin.Context) {
 	metrics.WithTiming(c, "featureState.single", func() {
-		feature, err := store.GetFeature(c, c.Query(namespaceQuery), c.Param(keyParam))
-		if err != nil {
-			c.AbortWithError(http.StatusInternalServerError, err)
-			return
-		}
-		if feature == nil {
-			c.AbortWithStatus(http.StatusNotFound)
-			return
-		}
+		writeFeatureState(c, queryContext(c))
+	})
+}
 
-		enabled, err := processor.ProcessGate(feature.Gate, c.Query(actorsQuery), c.Query(groupsQuery))
-		if err != nil {
-			c.AbortWithError(http.StatusInternalServerError, err)
+// PostFeatureState will retrieve the current gate state of a feature, given an
+// evaluation context in the request body.
+func PostFeatureState(c *gin.Context) {
+	metrics.WithTiming(c, "featureState.evaluate", func() {
+		var in api.EvaluationRequest
+		if err := c.BindJSON(&in); err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
 			return
 		}
-
-		state := api.FeatureState{Key: feature.Key, Enabled: enabled}
-		if feature.Namespace != "" {
-			state.Namespace = feature.Namespace
-		}
-
-		c.IndentedJSON(http.StatusOK, state)
+		writeFeatureState(c, evaluationContext(c, in.Context))
 	})
 }
 
 // PostBatchFeatureState will return the current gate state of a group of features
-// given optional actors and groups.
-//
-// TODO For now just iterating serially over features. This could easily be more
-// efficient with a pipeline.
+// given optional actors, groups and attributes.
 func PostBatchFeatureState(c *gin.Context) {
 	metrics.WithTiming(c, "featureState.batch", func() {
 		var in api.BatchFeatureStateRequest
@@ -172,57 +304,271 @@ func PostBatchFeatureState(c *gin.Context) {
 			c.AbortWithError(http.StatusBadRequest, err)
 			return
 		}
+		writeBatchFeatureState(c, in.NamespacedFeatures, in.Features, queryContext(c))
+	})
+}
 
-		if len(in.NamespacedFeatures) == 0 && len(in.Features) == 0 {
-			c.AbortWithError(http.StatusBadRequest, errors.New("no features to process"))
+// PostBatchEvaluation will return the current gate state of a group of
+// features, given an evaluation context in the request body.
+func PostBatchEvaluation(c *gin.Context) {
+	metrics.WithTiming(c, "featureState.batchEvaluate", func() {
+		var in api.BatchEvaluationRequest
+		if err := c.BindJSON(&in); err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
 			return
 		}
+		writeBatchFeatureState(c, in.NamespacedFeatures, in.Features, evaluationContext(c, in.Context))
+	})
+}
 
-		resp := api.BatchFeatureState{}
+// dependentFeatures returns the IDs of all features with a prerequisite on the
+// given feature.
+func dependentFeatures(c *gin.Context, feature *model.Feature) ([]string, error) {
+	features, err := store.GetAllFeatures(c)
+	if err != nil {
+		return nil, err
+	}
+	var dependents []string
+	for _, f := range features {
+		if f.DependsOn(feature.Namespace, feature.Key) {
+			dependents = append(dependents, f.ID())
+		}
+	}
+	return dependents, nil
+}
 
-		var all []*model.Feature
-		for ns, requestedFeatures := range in.NamespacedFeatures {
-			features, err := store.GetFeatureList(c, ns)
-			if err != nil {
-				c.AbortWithError(http.StatusInternalServerError, err)
-				return
-			}
-			for _, f := range features {
-				if strutil.StringInSlice(f.Key, requestedFeatures) {
-					all = append(all, f)
-				}
-			}
+func writeFeatureState(c *gin.Context, ctx *processor.Context) {
+	setLookups(c, ctx)
+	feature, err := store.GetFeature(c, c.Query(namespaceQuery), c.Param(keyParam))
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if feature == nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	state, err := featureState(feature, ctx, c.Query(debugQuery) == "true")
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	setOverdueHeader(c, feature)
+	trackUsage(c, feature, state)
+	exposeFeature(c, ctx, feature, state)
+
+	c.IndentedJSON(http.StatusOK, state)
+}
+
+// TODO For now just iterating serially over features. This could easily be more
+// efficient with a pipeline.
+func writeBatchFeatureState(c *gin.Context, namespacedFeatures api.NamespacedFeatures, globalFeatures []string, ctx *processor.Context) {
+	if len(namespacedFeatures) == 0 && len(globalFeatures) == 0 {
+		c.AbortWithError(http.StatusBadRequest, errors.New("no features to process"))
+		return
+	}
+
+	setLookups(c, ctx)
+	debug := c.Query(debugQuery) == "true"
+	resp := api.BatchFeatureState{}
+
+	var all []*model.Feature
+	for ns, requestedFeatures := range namespacedFeatures {
+		features, err := store.GetFeatureList(c, ns)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
 		}
-		if len(in.Features) > 0 {
-			features, err := store.GetFeatureList(c, "")
-			if err != nil {
-				c.AbortWithError(http.StatusInternalServerError, err)
-				return
+		for _, f := range features {
+			if strutil.StringInSlice(f.Key, requestedFeatures) {
+				all = append(all, f)
 			}
-			for _, f := range features {
-				if strutil.StringInSlice(f.Key, in.Features) {
-					all = append(all, f)
-				}
+		}
+	}
+	if len(globalFeatures) > 0 {
+		features, err := store.GetFeatureList(c, "")
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		for _, f := range features {
+			if strutil.StringInSlice(f.Key, globalFeatures) {
+				all = append(all, f)
 			}
 		}
+	}
 
-		for _, f := range all {
-			enabled, err := processor.ProcessGate(f.Gate, c.Query(actorsQuery), c.Query(groupsQuery))
-			if err != nil {
-				c.AbortWithError(http.StatusInternalServerError, err)
-				return
-			}
+	for _, f := range all {
+		state, err := featureState(f, ctx, debug)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		resp.States = append(resp.States, state)
+		trackUsage(c, f, state)
+		exposeFeature(c, ctx, f, state)
+	}
+	setOverdueHeader(c, all...)
+	c.IndentedJSON(http.StatusOK, resp)
+}
 
-			state := api.FeatureState{Key: f.Key, Enabled: enabled}
-			if f.Namespace != "" {
-				state.Namespace = f.Namespace
-			}
-			resp.States = append(resp.States, state)
+// featureState evaluates the feature for the context. In debug mode, the state
+// includes the reason for the evaluation.
+func featureState(f *model.Feature, ctx *processor.Context, debug bool) (api.FeatureState, error) {
+	evaluation, err := processor.ProcessFeature(f, ctx)
+	if err != nil {
+		return api.FeatureState{}, err
+	}
+
+	state := api.FeatureState{Key: f.Key, Enabled: evaluation.Enabled}
+	if f.Namespace != "" {
+		state.Namespace = f.Namespace
+	}
+	if evaluation.Variant != nil {
+		state.Variant = evaluation.Variant.Name
+	}
+	if evaluation.Value != "" {
+		state.Value = decodeValue(f, evaluation.Value)
+	}
+	if debug {
+		state.Reason = &api.EvaluationReason{
+			Kind:         evaluation.Reason,
+			GateType:     evaluation.GateType,
+			Actor:        evaluation.Actor,
+			Group:        evaluation.Group,
+			Segment:      evaluation.Segment,
+			CIDR:         evaluation.CIDR,
+			Bucket:       evaluation.Bucket,
+			Prerequisite: evaluation.Prerequisite,
 		}
-		c.IndentedJSON(http.StatusOK, resp)
+	}
+	return state, nil
+}
+
+// trackUsage counts the evaluation for the request's OAuth client, if usage is
+// tracked.
+func trackUsage(c *gin.Context, f *model.Feature, state api.FeatureState) {
+	tracker := usage.FromContext(c)
+	if tracker == nil {
+		return
+	}
+	outcome := state.Variant
+	if outcome == "" {
+		outcome = model.DisabledOutcome
+		if state.Enabled {
+			outcome = model.EnabledOutcome
+		}
+	}
+	tracker.Track(f, session.ClientID(c), outcome)
+}
+
+// exposeFeature exports the actor's exposure to the evaluated feature, if
+// exposures are exported. Evaluations without an actor are not exposures.
+func exposeFeature(c *gin.Context, ctx *processor.Context, f *model.Feature, state api.FeatureState) {
+	exporter := exposure.FromContext(c)
+	if exporter == nil || len(ctx.Actors) == 0 {
+		return
+	}
+	exporter.Expose(exposure.Event{
+		Namespace:   f.Namespace,
+		Key:         f.Key,
+		Actor:       ctx.Actors[0],
+		Enabled:     state.Enabled,
+		Variant:     state.Variant,
+		Environment: ctx.Environment,
+		ClientID:    session.ClientID(c),
+		Timestamp:   time.Now().UTC(),
 	})
 }
 
+// setOverdueHeader tells callers which of the evaluated features are past their
+// removal date, so that they can be cleaned up.
+func setOverdueHeader(c *gin.Context, features ...*model.Feature) {
+	now := time.Now().UTC()
+	var overdue []string
+	for _, f := range features {
+		if f.Overdue(now) {
+			overdue = append(overdue, f.ID())
+		}
+	}
+	if len(overdue) > 0 {
+		c.Writer.Header().Set(overdueHeader, strings.Join(overdue, ","))
+	}
+}
+
+// decodeValue decodes a value to the feature's type. Values that were saved
+// before types were validated fall back to the raw string.
+func decodeValue(f *model.Feature, value string) interface{} {
+	v, err := f.DecodeValue(value)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err": err,
+			"key": f.Key,
+			"ns":  f.Namespace,
+		}).Warn("Could not decode feature value")
+		return value
+	}
+	return v
+}
+
+// queryContext builds the gate evaluation context from the state endpoint
+// query params.
+func queryContext(c *gin.Context) *processor.Context {
+	ctx := processor.NewContext(c.Query(actorsQuery), c.Query(groupsQuery))
+	ctx.IP = requestIP(c, c.Query(ipQuery))
+	for k, v := range c.Request.URL.Query() {
+		if strings.HasPrefix(k, attributeQueryPrefix) && len(v) > 0 {
+			if ctx.Attributes == nil {
+				ctx.Attributes = make(map[string]string)
+			}
+			ctx.Attributes[strings.TrimPrefix(k, attributeQueryPrefix)] = v[0]
+		}
+	}
+	return ctx
+}
+
+// evaluationContext converts the request body evaluation context into the gate
+// evaluation context.
+func evaluationContext(c *gin.Context, in api.EvaluationContext) *processor.Context {
+	ctx := &processor.Context{
+		Groups:     in.Groups,
+		Attributes: in.Attributes,
+		IP:         requestIP(c, in.IP),
+	}
+	if in.Actor != "" {
+		ctx.Actors = []string{in.Actor}
+	}
+	return ctx
+}
+
+// requestIP returns the explicitly passed IP, or else the caller's IP. Only
+// service-scoped callers can pass an IP, since others could use it to get
+// around the trusted proxies. An invalid IP is not in any CIDR range.
+func requestIP(c *gin.Context, explicit string) net.IP {
+	if explicit != "" && session.IsService(c) {
+		return net.ParseIP(explicit)
+	}
+	return clientip.FromContext(c)
+}
+
+// featureLookup resolves features from the request's storage backend.
+func featureLookup(c *gin.Context) model.FeatureLookup {
+	return func(namespace string, key string) (*model.Feature, error) {
+		return store.GetFeature(c, namespace, key)
+	}
+}
+
+// setLookups resolves the prerequisites and segments of evaluated features
+// from the request's storage backend, in the requested environment.
+func setLookups(c *gin.Context, ctx *processor.Context) {
+	ctx.Environment = c.Query(envQuery)
+	ctx.FeatureLookup = featureLookup(c)
+	ctx.SegmentLookup = func(name string) (*model.Segment, error) {
+		return store.GetSegment(c, name)
+	}
+}
+
 func saveBreadcrumb(c *gin.Context, breadcrumb *model.Breadcrumb) {
 	if err := store.SaveBreadcrumb(c, breadcrumb); err != nil {
 		correlationid.Logger(c).WithFields(logrus.Fields{
@@ -12,8 +12,11 @@ import (
 	"time"
 
 	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/exposure"
 	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/clientip"
 	"github.com/robzienert/lever/router/middleware/context"
+	"github.com/robzienert/lever/router/middleware/session"
 	"github.com/robzienert/lever/store"
 	"github.com/robzienert/lever/store/memory"
 	"github.com/robzienert/lever/store/mock"
@@ -27,6 +30,26 @@ func jsonString(data interface{}) string {
 	return string(out)
 }
 
+// booleanState is the state of a boolean feature whose value is "true".
+func booleanState(key string, enabled bool) api.FeatureState {
+	state := api.FeatureState{Key: key, Enabled: enabled}
+	if enabled {
+		state.Value = true
+	}
+	return state
+}
+
+type exposureRecorder struct {
+	events []exposure.Event
+}
+
+func (r *exposureRecorder) Write(events []exposure.Event) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *exposureRecorder) Close() error { return nil }
+
 type FeaturesTestSuite struct {
 	suite.Suite
 }
@@ -46,6 +69,21 @@ func (suite *FeaturesTestSuite) serveEndpoint(store store.Store, method string,
 	return resp
 }
 
+func (suite *FeaturesTestSuite) putFeature(store store.Store, f model.Feature) *httptest.ResponseRecorder {
+	return suite.serveEndpoint(store, "PUT", "/features/"+f.Key, func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(jsonString(f)))
+}
+
+func (suite *FeaturesTestSuite) saveFeature(store store.Store, f model.Feature) *model.Feature {
+	resp := suite.putFeature(store, f)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	featureResp := &api.FeatureResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), featureResp))
+	return featureResp.Feature
+}
+
 func (suite *FeaturesTestSuite) TestFeatureGetAll_Empty() {
 	memStore := memory.Load()
 	resp := suite.serveEndpoint(memStore, "GET", "/features", func(router *gin.Engine) {
@@ -272,6 +310,116 @@ func (suite *FeaturesTestSuite) TestFeaturePut_OK() {
 	breadcrumbs, err := memStore.Breadcrumbs().GetList()
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), breadcrumbs, 2, "no Breadcrumbs found after destructive actions")
+	for _, b := range breadcrumbs {
+		assert.Equal(suite.T(), "one", b.Fields["key"], b.Action)
+	}
+}
+
+func (suite *FeaturesTestSuite) TestFeaturePut_InvalidGate() {
+	f, err := json.Marshal(model.Feature{
+		Key:   "one",
+		Type:  "java.lang.Boolean",
+		Value: "true",
+		Gate: &model.Gate{
+			Rules: []model.Rule{{Attribute: "country", Operator: "like", Values: []string{"DE"}}},
+		},
+	})
+	assert.NoError(suite.T(), err)
+
+	memStore := memory.Load()
+	resp := suite.serveEndpoint(memStore, "PUT", "/features/one", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(string(f)))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+
+	features, err := memStore.Features().GetList()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), features, 0)
+}
+
+func (suite *FeaturesTestSuite) TestFeaturePut_Seed() {
+	memStore := memory.Load()
+	// New features are salted by namespace and key, and keep their seed when it
+	// is not sent with an update.
+	created := suite.saveFeature(memStore, model.Feature{Namespace: "mobile.ios", Key: "one", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Value: "true"}})
+	assert.Equal(suite.T(), "mobile.ios/one", created.Gate.Seed)
+	updated := suite.saveFeature(memStore, model.Feature{Namespace: "mobile.ios", Key: "one", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Value: "false"}})
+	assert.Equal(suite.T(), "mobile.ios/one", updated.Gate.Seed)
+
+	// Features created before seeds existed stay unsalted until one is set.
+	legacy := model.Feature{Key: "two", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Value: "true"}}
+	assert.NoError(suite.T(), memStore.Features().Upsert(&legacy))
+	updated = suite.saveFeature(memStore, model.Feature{Key: "two", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Value: "false"}})
+	assert.Empty(suite.T(), updated.Gate.Seed)
+	updated = suite.saveFeature(memStore, model.Feature{Key: "two", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Value: "false", Seed: "c0ffee"}})
+	assert.Equal(suite.T(), "c0ffee", updated.Gate.Seed)
+}
+
+func (suite *FeaturesTestSuite) TestFeaturePut_Rollout() {
+	memStore := memory.Load()
+	feature := func(rollout *model.Rollout) model.Feature {
+		return model.Feature{Key: "one", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Actors: []string{"a"}, ActorPercent: 50}, Rollout: rollout}
+	}
+
+	// New plans start at their first step and take over the actor percent.
+	created := suite.saveFeature(memStore, feature(&model.Rollout{Steps: []int{1, 5, 100}, Interval: "1h"}))
+	assert.Equal(suite.T(), model.RolloutRunningState, created.Rollout.State)
+	assert.Equal(suite.T(), 0, created.Rollout.Step)
+	assert.NotNil(suite.T(), created.Rollout.NextStepAt)
+	assert.Equal(suite.T(), 1, created.Gate.ActorPercent)
+
+	// Unchanged plans keep their progress.
+	stored, _ := memStore.Features().Get("one")
+	stored.Rollout.Step = 1
+	assert.NoError(suite.T(), memStore.Features().Upsert(stored))
+	updated := suite.saveFeature(memStore, feature(&model.Rollout{Steps: []int{1, 5, 100}, Interval: "1h"}))
+	assert.Equal(suite.T(), 1, updated.Rollout.Step)
+	assert.Equal(suite.T(), 5, updated.Gate.ActorPercent)
+
+	// Changed plans start over.
+	updated = suite.saveFeature(memStore, feature(&model.Rollout{Steps: []int{10, 100}, Interval: "1h"}))
+	assert.Equal(suite.T(), 0, updated.Rollout.Step)
+	assert.Equal(suite.T(), 10, updated.Gate.ActorPercent)
+
+	resp := suite.putFeature(memStore, feature(&model.Rollout{Steps: []int{50, 10}, Interval: "1h"}))
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *FeaturesTestSuite) TestFeaturePut_Prerequisites() {
+	memStore := memory.Load()
+	feature := func(key string, prerequisites ...model.Prerequisite) model.Feature {
+		return model.Feature{Key: key, Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Value: "true"}, Prerequisites: prerequisites}
+	}
+
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.putFeature(memStore, feature("a", model.Prerequisite{Key: "b", Enabled: true})).Code, "missing prerequisite")
+	assert.Equal(suite.T(), http.StatusOK, suite.putFeature(memStore, feature("b")).Code)
+	assert.Equal(suite.T(), http.StatusOK, suite.putFeature(memStore, feature("a", model.Prerequisite{Key: "b", Enabled: true})).Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.putFeature(memStore, feature("b", model.Prerequisite{Key: "a", Enabled: true})).Code, "cycle")
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.putFeature(memStore, feature("b", model.Prerequisite{Key: "b", Enabled: true})).Code, "self-cycle")
+
+	stored, _ := memStore.Features().Get("b")
+	assert.Empty(suite.T(), stored.Prerequisites)
+}
+
+func (suite *FeaturesTestSuite) TestFeatureDelete_Dependents() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{Namespace: "api", Key: "one", Gate: &model.Gate{}})
+	memStore.Features().Upsert(&model.Feature{Key: "two", Gate: &model.Gate{}, Prerequisites: []model.Prerequisite{{Namespace: "api", Key: "one", Enabled: true}}})
+
+	resp := suite.serveEndpoint(memStore, "DELETE", "/features/one?ns=api", func(router *gin.Engine) {
+		router.DELETE("/features/:key", DeleteFeature)
+	}, nil)
+	assert.Equal(suite.T(), http.StatusConflict, resp.Code)
+
+	resp = suite.serveEndpoint(memStore, "DELETE", "/features/one?ns=api&force=true", func(router *gin.Engine) {
+		router.DELETE("/features/:key", DeleteFeature)
+	}, nil)
+	assert.Equal(suite.T(), http.StatusNoContent, resp.Code)
+
+	features, err := memStore.Features().GetListByNamespace("api")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), features, 0)
 }
 
 func (suite *FeaturesTestSuite) TestFeatureDelete_GetStoreError() {
@@ -301,6 +449,9 @@ func (suite *FeaturesTestSuite) TestFeatureDelete_DeleteStoreError() {
 		GetFn: func(ns string, key string) (*model.Feature, error) {
 			return &model.Feature{}, nil
 		},
+		GetAllFn: func() ([]*model.Feature, error) {
+			return nil, nil
+		},
 		DeleteFn: func(feature *model.Feature) error {
 			return errors.New("oh no")
 		},
@@ -387,6 +538,563 @@ func (suite *FeaturesTestSuite) TestFeatureSingleState_OK() {
 	assert.True(suite.T(), stateResp.Enabled)
 }
 
+func (suite *FeaturesTestSuite) TestFeatureSingleState_Attributes() {
+	memStore := memory.Load()
+
+	f := model.Feature{
+		Key:   "one",
+		Type:  "java.lang.Boolean",
+		Value: "true",
+		Gate: &model.Gate{
+			Rules: []model.Rule{
+				{Attribute: "country", Operator: model.EqualsOperator, Values: []string{"DE"}},
+				{Attribute: "appVersion", Operator: model.SemverGreaterThanOrEqualOperator, Values: []string{"5.2.0"}},
+			},
+		},
+	}
+	assert.NoError(suite.T(), memStore.Features().Upsert(&f))
+
+	for endpoint, expected := range map[string]bool{
+		"/features/one/state?attr.country=DE&attr.appVersion=5.2.1": true,
+		"/features/one/state?attr.country=DE&attr.appVersion=5.1.9": false,
+		"/features/one/state?attr.country=DE":                       false,
+	} {
+		resp := suite.serveEndpoint(memStore, "GET", endpoint, func(router *gin.Engine) {
+			router.GET("/features/:key/state", GetFeatureState)
+		}, nil)
+
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+		stateResp := &api.FeatureState{}
+		assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), stateResp))
+		assert.Equal(suite.T(), expected, stateResp.Enabled, endpoint)
+	}
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleStatePost_BadRequest() {
+	resp := suite.serveEndpoint(memory.Load(), "POST", "/features/one/state", func(router *gin.Engine) {
+		router.POST("/features/:key/state", PostFeatureState)
+	}, strings.NewReader("bad data"))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleStatePost_OK() {
+	memStore := memory.Load()
+
+	f := model.Feature{
+		Namespace: "mobile.ios",
+		Key:       "one",
+		Type:      "java.lang.Boolean",
+		Value:     "true",
+		Gate: &model.Gate{
+			Actors: []string{"Zienert, Rob"},
+			Rules:  []model.Rule{{Attribute: "plan", Operator: model.EqualsOperator, Values: []string{"pro"}}},
+		},
+	}
+	assert.NoError(suite.T(), memStore.Features().Upsert(&f))
+
+	for _, tt := range []struct {
+		context  api.EvaluationContext
+		expected bool
+	}{
+		{api.EvaluationContext{Actor: "Zienert, Rob"}, true},
+		{api.EvaluationContext{Actor: "Zienert"}, false},
+		{api.EvaluationContext{Attributes: map[string]string{"plan": "pro"}}, true},
+	} {
+		req, err := json.Marshal(&api.EvaluationRequest{Context: tt.context})
+		assert.NoError(suite.T(), err)
+
+		resp := suite.serveEndpoint(memStore, "POST", "/features/one/state?ns=mobile.ios", func(router *gin.Engine) {
+			router.POST("/features/:key/state", PostFeatureState)
+		}, strings.NewReader(string(req)))
+
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+		stateResp := &api.FeatureState{}
+		assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), stateResp))
+		assert.Equal(suite.T(), "mobile.ios", stateResp.Namespace)
+		assert.Equal(suite.T(), tt.expected, stateResp.Enabled, string(req))
+	}
+}
+
+func (suite *FeaturesTestSuite) TestFeatureBatchEvaluation_EmptyRequest() {
+	req, err := json.Marshal(&api.BatchEvaluationRequest{
+		Context: api.EvaluationContext{Actor: "one"},
+	})
+	assert.NoError(suite.T(), err)
+
+	resp := suite.serveEndpoint(memory.Load(), "POST", "/state", func(router *gin.Engine) {
+		router.POST("/state", PostBatchEvaluation)
+	}, strings.NewReader(string(req)))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *FeaturesTestSuite) TestFeatureBatchEvaluation_OK() {
+	memStore := memory.Load()
+
+	f1 := model.Feature{
+		Key:   "globalKeyWithGroup",
+		Type:  "java.lang.Boolean",
+		Value: "true",
+		Gate: &model.Gate{
+			Groups: []string{"beta, internal"},
+		},
+	}
+	assert.NoError(suite.T(), memStore.Features().Upsert(&f1))
+	f2 := model.Feature{
+		Namespace: "mobile.ios",
+		Key:       "namespacedKeyWithRules",
+		Type:      "java.lang.Boolean",
+		Value:     "true",
+		Gate: &model.Gate{
+			Rules: []model.Rule{{Attribute: "country", Operator: model.InOperator, Values: []string{"DE", "FR"}}},
+		},
+	}
+	assert.NoError(suite.T(), memStore.Features().Upsert(&f2))
+
+	req, err := json.Marshal(&api.BatchEvaluationRequest{
+		Context: api.EvaluationContext{
+			Actor:      "one",
+			Groups:     []string{"beta, internal"},
+			Attributes: map[string]string{"country": "US"},
+		},
+		NamespacedFeatures: api.NamespacedFeatures{
+			"mobile.ios": []string{"namespacedKeyWithRules"},
+		},
+		Features: []string{"globalKeyWithGroup"},
+	})
+	assert.NoError(suite.T(), err)
+
+	resp := suite.serveEndpoint(memStore, "POST", "/state", func(router *gin.Engine) {
+		router.POST("/state", PostBatchEvaluation)
+	}, strings.NewReader(string(req)))
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	stateResp := &api.BatchFeatureState{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), stateResp))
+	assert.Len(suite.T(), stateResp.States, 2)
+	for _, state := range stateResp.States {
+		if state.Key == "globalKeyWithGroup" {
+			assert.True(suite.T(), state.Enabled)
+		} else {
+			assert.False(suite.T(), state.Enabled)
+		}
+	}
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_Variants() {
+	memStore := memory.Load()
+
+	f := model.Feature{
+		Key:   "one",
+		Type:  "java.lang.String",
+		Value: "blue",
+		Gate: &model.Gate{
+			Actors: []string{"robzienert"},
+		},
+		Variants: []model.Variant{
+			{Name: "control", Weight: 1, Value: "blue"},
+			{Name: "treatment", Weight: 0, Value: "green"},
+		},
+	}
+	assert.NoError(suite.T(), memStore.Features().Upsert(&f))
+
+	resp := suite.serveEndpoint(memStore, "GET", "/features/one/state?actors=robzienert", func(router *gin.Engine) {
+		router.GET("/features/:key/state", GetFeatureState)
+	}, nil)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	stateResp := &api.FeatureState{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), stateResp))
+	assert.True(suite.T(), stateResp.Enabled)
+	assert.Equal(suite.T(), "control", stateResp.Variant)
+	assert.Equal(suite.T(), "blue", stateResp.Value)
+
+	resp = suite.serveEndpoint(memStore, "GET", "/features/one/state?actors=someoneElse", func(router *gin.Engine) {
+		router.GET("/features/:key/state", GetFeatureState)
+	}, nil)
+
+	stateResp = &api.FeatureState{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), stateResp))
+	assert.False(suite.T(), stateResp.Enabled)
+	assert.Empty(suite.T(), stateResp.Variant)
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_TypedValues() {
+	memStore := memory.Load()
+
+	for value, valid := range map[string]bool{"42": true, "forty-two": false} {
+		f := model.Feature{Key: "limit", Type: model.IntValueType, Value: value, Gate: &model.Gate{Value: "true"}}
+		resp := suite.serveEndpoint(memStore, "PUT", "/features/limit", func(router *gin.Engine) {
+			router.PUT("/features/:key", PutFeature)
+		}, strings.NewReader(jsonString(f)))
+		if valid {
+			assert.Equal(suite.T(), http.StatusOK, resp.Code, value)
+		} else {
+			assert.Equal(suite.T(), http.StatusBadRequest, resp.Code, value)
+		}
+	}
+
+	f := model.Feature{
+		Key:    "theme",
+		Type:   model.JSONValueType,
+		Value:  `{"color":"blue"}`,
+		Schema: json.RawMessage(`{"type":"object","required":["color"]}`),
+		Gate:   &model.Gate{Value: "true"},
+	}
+	resp := suite.serveEndpoint(memStore, "PUT", "/features/theme", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(jsonString(f)))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	f.Value = `{"size":1}`
+	resp = suite.serveEndpoint(memStore, "PUT", "/features/theme", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(jsonString(f)))
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+
+	f.Value = `{"color":"green"}`
+	f.Schema = json.RawMessage(`{"type":"object"}`)
+	resp = suite.serveEndpoint(memStore, "PUT", "/features/theme", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(jsonString(f)))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	stored, err := memStore.Features().Get("theme")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), json.RawMessage(`{"type":"object"}`), stored.Schema)
+
+	resp = suite.serveEndpoint(memStore, "GET", "/features/limit/state", func(router *gin.Engine) {
+		router.GET("/features/:key/state", GetFeatureState)
+	}, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"key":"limit","enabled":true,"value":42}`, resp.Body.String())
+
+	resp = suite.serveEndpoint(memStore, "GET", "/features/theme/state", func(router *gin.Engine) {
+		router.GET("/features/:key/state", GetFeatureState)
+	}, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"key":"theme","enabled":true,"value":{"color":"green"}}`, resp.Body.String())
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_OffValue() {
+	memStore := memory.Load()
+
+	f := model.Feature{
+		Key:      "theme",
+		Type:     model.JSONValueType,
+		Value:    `{"color":"green"}`,
+		OffValue: `{"color":"blue"}`,
+		Gate:     &model.Gate{Actors: []string{"one"}},
+	}
+	resp := suite.serveEndpoint(memStore, "PUT", "/features/theme", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(jsonString(f)))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	for actor, expected := range map[string]string{
+		"one": `{"key":"theme","enabled":true,"value":{"color":"green"}}`,
+		"two": `{"key":"theme","enabled":false,"value":{"color":"blue"}}`,
+	} {
+		resp := suite.serveEndpoint(memStore, "GET", "/features/theme/state?actors="+actor, func(router *gin.Engine) {
+			router.GET("/features/:key/state", GetFeatureState)
+		}, nil)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+		assert.JSONEq(suite.T(), expected, resp.Body.String(), actor)
+	}
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_Environments() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{
+		Key:          "foo",
+		Type:         "java.lang.Boolean",
+		Value:        "true",
+		Gate:         &model.Gate{Value: "false"},
+		Environments: map[string]*model.Gate{"staging": {Value: "true"}},
+	})
+
+	for env, enabled := range map[string]bool{"": false, "staging": true, "prod": false} {
+		resp := suite.serveEndpoint(memStore, "GET", "/features/foo/state?env="+env, func(router *gin.Engine) {
+			router.GET("/features/:key/state", GetFeatureState)
+		}, nil)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+		assert.JSONEq(suite.T(), jsonString(booleanState("foo", enabled)), resp.Body.String(), env)
+	}
+
+	resp := suite.serveEndpoint(memStore, "GET", "/features/foo?env=staging", func(router *gin.Engine) {
+		router.GET("/features/:key", GetFeature)
+	}, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	featureResp := &api.FeatureResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), featureResp))
+	assert.Equal(suite.T(), "true", featureResp.Feature.Gate.Value)
+	assert.Empty(suite.T(), featureResp.Feature.Environments)
+}
+
+func (suite *FeaturesTestSuite) TestFeatureState_Overdue() {
+	memStore := memory.Load()
+	removeBy := time.Now().UTC().AddDate(0, 0, -1)
+	memStore.Features().Upsert(&model.Feature{
+		Key:       "foo",
+		Type:      "java.lang.Boolean",
+		Value:     "true",
+		Gate:      &model.Gate{Value: "true"},
+		Lifecycle: &model.Lifecycle{Kind: model.TemporaryLifecycle, RemoveBy: &removeBy},
+	})
+	memStore.Features().Upsert(&model.Feature{
+		Key:   "bar",
+		Type:  "java.lang.Boolean",
+		Value: "true",
+		Gate:  &model.Gate{Value: "true"},
+	})
+
+	resp := suite.serveEndpoint(memStore, "GET", "/features/foo/state", func(router *gin.Engine) {
+		router.GET("/features/:key/state", GetFeatureState)
+	}, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Equal(suite.T(), "foo", resp.Header().Get(overdueHeader))
+
+	resp = suite.serveEndpoint(memStore, "GET", "/features/bar/state", func(router *gin.Engine) {
+		router.GET("/features/:key/state", GetFeatureState)
+	}, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Empty(suite.T(), resp.Header().Get(overdueHeader))
+
+	resp = suite.serveEndpoint(memStore, "POST", "/features", func(router *gin.Engine) {
+		router.POST("/features", PostBatchFeatureState)
+	}, strings.NewReader(`{"features":["foo","bar"]}`))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Equal(suite.T(), "foo", resp.Header().Get(overdueHeader))
+}
+
+func (suite *FeaturesTestSuite) TestFeatureState_Exposure() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{
+		Namespace: "mobile",
+		Key:       "foo",
+		Type:      "java.lang.String",
+		Value:     "on",
+		Gate:      &model.Gate{Value: "true"},
+		Variants:  []model.Variant{{Name: "treatment", Weight: 1, Value: "green"}},
+	})
+	recorder := &exposureRecorder{}
+	exporter := exposure.NewExporter(exposure.ExporterSpec{Sink: recorder, BufferSize: 10, BatchSize: 10, FlushInterval: time.Hour, SamplePercent: 100})
+	exporter.Start()
+
+	for _, query := range []string{"?ns=mobile", "?ns=mobile&actors=one&env=prod"} {
+		resp := suite.serveEndpoint(memStore, "GET", "/features/foo/state"+query, func(router *gin.Engine) {
+			router.Use(context.SetExposureExporter(exporter))
+			router.GET("/features/:key/state", GetFeatureState)
+		}, nil)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code, query)
+	}
+	exporter.Close()
+
+	if assert.Len(suite.T(), recorder.events, 1, "evaluations without an actor are not exposures") {
+		e := recorder.events[0]
+		assert.Equal(suite.T(), "mobile", e.Namespace)
+		assert.Equal(suite.T(), "one", e.Actor)
+		assert.True(suite.T(), e.Enabled)
+		assert.Equal(suite.T(), "treatment", e.Variant)
+		assert.Equal(suite.T(), "prod", e.Environment)
+		assert.False(suite.T(), e.Timestamp.IsZero())
+	}
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_CIDRs() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{
+		Key:   "foo",
+		Type:  "java.lang.Boolean",
+		Value: "true",
+		Gate:  &model.Gate{CIDRs: []string{"10.0.0.0/8"}},
+	})
+
+	service := func(c *gin.Context) {
+		c.Set(session.ServiceKey, true)
+	}
+	for query, enabled := range map[string]bool{"": false, "ip=10.1.2.3": true, "ip=192.168.0.1": false, "ip=invalid": false} {
+		resp := suite.serveEndpoint(memStore, "GET", "/features/foo/state?"+query, func(router *gin.Engine) {
+			router.Use(service)
+			router.GET("/features/:key/state", GetFeatureState)
+		}, nil)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+		assert.JSONEq(suite.T(), jsonString(booleanState("foo", enabled)), resp.Body.String(), query)
+	}
+
+	resp := suite.serveEndpoint(memStore, "POST", "/features/foo/state?debug=true", func(router *gin.Engine) {
+		router.Use(service)
+		router.POST("/features/:key/state", PostFeatureState)
+	}, strings.NewReader(`{"context":{"ip":"10.1.2.3"}}`))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	stateResp := &api.FeatureState{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), stateResp))
+	assert.True(suite.T(), stateResp.Enabled)
+	assert.Equal(suite.T(), "10.0.0.0/8", stateResp.Reason.CIDR)
+
+	// The caller's IP is used unless one is passed explicitly.
+	router := gin.New()
+	router.Use(context.SetStore(memStore))
+	router.Use(clientip.Set(nil))
+	router.Use(service)
+	router.GET("/features/:key/state", GetFeatureState)
+	for query, enabled := range map[string]bool{"": true, "?ip=192.168.0.1": false} {
+		req, _ := http.NewRequest("GET", "/features/foo/state"+query, nil)
+		req.RemoteAddr = "10.1.2.3:4711"
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+		assert.JSONEq(suite.T(), jsonString(booleanState("foo", enabled)), resp.Body.String(), query)
+	}
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_CIDRsMobile() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{
+		Key:   "foo",
+		Type:  "java.lang.Boolean",
+		Value: "true",
+		Gate:  &model.Gate{CIDRs: []string{"10.0.0.0/8"}},
+	})
+
+	// Callers without a service-scoped token cannot pass another IP.
+	router := gin.New()
+	router.Use(context.SetStore(memStore))
+	router.Use(clientip.Set(nil))
+	router.GET("/features/:key/state", GetFeatureState)
+	router.POST("/features/:key/state", PostFeatureState)
+	for query, enabled := range map[string]bool{"": false, "?ip=10.1.2.3": false} {
+		req, _ := http.NewRequest("GET", "/features/foo/state"+query, nil)
+		req.RemoteAddr = "192.168.0.1:4711"
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+		assert.JSONEq(suite.T(), jsonString(booleanState("foo", enabled)), resp.Body.String(), query)
+	}
+
+	req, _ := http.NewRequest("POST", "/features/foo/state", strings.NewReader(`{"context":{"ip":"192.168.0.1"}}`))
+	req.RemoteAddr = "10.1.2.3:4711"
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), jsonString(booleanState("foo", true)), resp.Body.String())
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_Prerequisites() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{Namespace: "api", Key: "backend", Gate: &model.Gate{Actors: []string{"one"}}})
+	memStore.Features().Upsert(&model.Feature{
+		Key:           "mobile",
+		Gate:          &model.Gate{Value: "true"},
+		Prerequisites: []model.Prerequisite{{Namespace: "api", Key: "backend", Enabled: true}},
+	})
+
+	for actor, enabled := range map[string]bool{"one": true, "two": false} {
+		resp := suite.serveEndpoint(memStore, "GET", "/features/mobile/state?actors="+actor, func(router *gin.Engine) {
+			router.GET("/features/:key/state", GetFeatureState)
+		}, nil)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+		assert.JSONEq(suite.T(), jsonString(api.FeatureState{Key: "mobile", Enabled: enabled}), resp.Body.String(), actor)
+	}
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_Segments() {
+	memStore := memory.Load()
+	memStore.Segments().Upsert(&model.Segment{Name: "beta", Actors: []string{"one"}})
+
+	f := model.Feature{Key: "foo", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Segments: []string{"beta"}}}
+	resp := suite.serveEndpoint(memStore, "PUT", "/features/foo", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(jsonString(f)))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	for actor, enabled := range map[string]bool{"one": true, "two": false} {
+		resp := suite.serveEndpoint(memStore, "GET", "/features/foo/state?actors="+actor, func(router *gin.Engine) {
+			router.GET("/features/:key/state", GetFeatureState)
+		}, nil)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+		assert.JSONEq(suite.T(), jsonString(booleanState("foo", enabled)), resp.Body.String(), actor)
+	}
+
+	f.Gate.Segments = []string{"missing"}
+	resp = suite.serveEndpoint(memStore, "PUT", "/features/foo", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(jsonString(f)))
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_Expression() {
+	memStore := memory.Load()
+
+	f := model.Feature{Key: "foo", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{
+		Expression: &model.Expression{Or: []model.Expression{
+			{Gate: &model.Gate{Actors: []string{"one"}}},
+			{And: []model.Expression{
+				{Gate: &model.Gate{Groups: []string{"staff"}}},
+				{Not: &model.Expression{Gate: &model.Gate{Rules: []model.Rule{
+					{Attribute: "country", Operator: model.EqualsOperator, Values: []string{"CN"}},
+				}}}},
+			}},
+		}},
+	}}
+	resp := suite.serveEndpoint(memStore, "PUT", "/features/foo", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(jsonString(f)))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	for query, enabled := range map[string]bool{
+		"actors=one&attr.country=CN":   true,
+		"groups=staff&attr.country=DE": true,
+		"groups=staff&attr.country=CN": false,
+		"actors=two&attr.country=DE":   false,
+	} {
+		resp := suite.serveEndpoint(memStore, "GET", "/features/foo/state?"+query, func(router *gin.Engine) {
+			router.GET("/features/:key/state", GetFeatureState)
+		}, nil)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+		assert.JSONEq(suite.T(), jsonString(booleanState("foo", enabled)), resp.Body.String(), query)
+	}
+
+	f.Gate.Expression = &model.Expression{And: []model.Expression{}}
+	resp = suite.serveEndpoint(memStore, "PUT", "/features/foo", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, strings.NewReader(jsonString(f)))
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *FeaturesTestSuite) TestFeatureSingleState_Debug() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{Actors: []string{"one"}}})
+
+	resp := suite.serveEndpoint(memStore, "GET", "/features/foo/state?actors=one", func(router *gin.Engine) {
+		router.GET("/features/:key/state", GetFeatureState)
+	}, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), jsonString(api.FeatureState{Key: "foo", Enabled: true}), resp.Body.String())
+
+	resp = suite.serveEndpoint(memStore, "GET", "/features/foo/state?actors=one&debug=true", func(router *gin.Engine) {
+		router.GET("/features/:key/state", GetFeatureState)
+	}, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	expected := api.FeatureState{
+		Key:     "foo",
+		Enabled: true,
+		Reason:  &api.EvaluationReason{Kind: "match", GateType: model.ActorsGateType, Actor: "one"},
+	}
+	assert.JSONEq(suite.T(), jsonString(expected), resp.Body.String())
+
+	resp = suite.serveEndpoint(memStore, "POST", "/features?debug=true", func(router *gin.Engine) {
+		router.POST("/features", PostBatchFeatureState)
+	}, strings.NewReader(`{"features":["foo"]}`))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	expected = api.FeatureState{Key: "foo", Reason: &api.EvaluationReason{Kind: "noMatch"}}
+	assert.JSONEq(suite.T(), jsonString(api.BatchFeatureState{States: []api.FeatureState{expected}}), resp.Body.String())
+}
+
 func (suite *FeaturesTestSuite) TestFeatureBatchState_BadRequest() {
 	resp := suite.serveEndpoint(memory.Load(), "POST", "/features", func(router *gin.Engine) {
 		router.POST("/features", PutFeature)
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/store"
+)
+
+const (
+	daysQuery = "days"
+
+	// defaultRolledOutDays is how long a feature must have been enabled for
+	// everyone before it is reported as stale.
+	defaultRolledOutDays = 30
+)
+
+// GetStaleReport lists features that should be removed: temporary features
+// past their removal date, and features that have been enabled for everyone
+// for at least the number of days in the "days" query param, 30 by default.
+// Permanent features are never reported.
+func GetStaleReport(c *gin.Context) {
+	days := defaultRolledOutDays
+	if q := c.Query(daysQuery); q != "" {
+		var err error
+		if days, err = strconv.Atoi(q); err != nil || days < 0 {
+			c.AbortWithError(http.StatusBadRequest, errors.New("days must be a non-negative integer"))
+			return
+		}
+	}
+
+	features, err := store.GetAllFeatures(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	breadcrumbs, err := store.GetBreadcrumbList(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	cutoff := now.AddDate(0, 0, -days)
+	report := api.StaleFeatureReport{Features: make([]api.StaleFeature, 0)}
+	for _, f := range features {
+		stale := api.StaleFeature{
+			Namespace: f.Namespace,
+			Key:       f.Key,
+			Lifecycle: f.Lifecycle,
+			Overdue:   f.Overdue(now),
+		}
+		if since, ok := f.RolledOutSince(breadcrumbs); ok && !since.After(cutoff) {
+			stale.RolledOutSince = &since
+		}
+		if stale.Overdue || stale.RolledOutSince != nil {
+			report.Features = append(report.Features, stale)
+		}
+	}
+
+	c.IndentedJSON(http.StatusOK, report)
+}
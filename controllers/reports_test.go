@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/context"
+	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReportsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ReportsTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *ReportsTestSuite) serveStaleReport(s store.Store, query string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(context.SetStore(s))
+	router.GET("/reports/stale", GetStaleReport)
+
+	req, _ := http.NewRequest("GET", "/reports/stale"+query, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func (suite *ReportsTestSuite) TestStaleReport_BadRequest() {
+	resp := suite.serveStaleReport(memory.Load(), "?days=soon")
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *ReportsTestSuite) TestStaleReport_Empty() {
+	resp := suite.serveStaleReport(memory.Load(), "")
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), `{"features":[]}`, resp.Body.String())
+}
+
+func (suite *ReportsTestSuite) TestStaleReport_OK() {
+	now := time.Now().UTC()
+	longAgo := now.AddDate(0, 0, -60)
+	recently := now.AddDate(0, 0, -10)
+
+	memStore := memory.Load()
+	for _, f := range []*model.Feature{
+		{Key: "overdue", Gate: &model.Gate{Value: "false"}, Lifecycle: &model.Lifecycle{Kind: model.TemporaryLifecycle, Owner: "search", RemoveBy: &recently}},
+		{Key: "rolledOut", Gate: &model.Gate{Value: "true"}, DateCreated: longAgo, LastUpdated: recently},
+		{Key: "recent", Gate: &model.Gate{Value: "true"}, DateCreated: longAgo, LastUpdated: recently},
+		{Key: "permanent", Gate: &model.Gate{Value: "true"}, Lifecycle: &model.Lifecycle{Kind: model.PermanentLifecycle}, LastUpdated: longAgo},
+		{Key: "partial", Gate: &model.Gate{PercentOfEveryone: 50}, LastUpdated: longAgo},
+	} {
+		memStore.Features().Upsert(f)
+	}
+	memStore.Breadcrumbs().Create(&model.Breadcrumb{
+		Action:      model.CreateFeatureAction,
+		DateCreated: longAgo,
+		Fields:      model.Fields{"key": "rolledOut", "ns": ""},
+	})
+
+	resp := suite.serveStaleReport(memStore, "")
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	report := api.StaleFeatureReport{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &report))
+	stale := make(map[string]api.StaleFeature)
+	for _, f := range report.Features {
+		stale[f.Key] = f
+	}
+	assert.Len(suite.T(), stale, 2)
+	assert.True(suite.T(), stale["overdue"].Overdue)
+	assert.Equal(suite.T(), "search", stale["overdue"].Lifecycle.Owner)
+	assert.Nil(suite.T(), stale["overdue"].RolledOutSince)
+	assert.False(suite.T(), stale["rolledOut"].Overdue)
+	assert.NotNil(suite.T(), stale["rolledOut"].RolledOutSince)
+
+	resp = suite.serveStaleReport(memStore, "?days=5")
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &report))
+	assert.Len(suite.T(), report.Features, 3)
+}
+
+func TestReportsTestSuite(t *testing.T) {
+	suite.Run(t, new(ReportsTestSuite))
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+)
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+var errPreconditionFailed = errors.New("feature revision does not match If-Match")
+
+// featureETag returns the strong entity tag of the feature's revision.
+func featureETag(f *model.Feature) string {
+	return `"` + strconv.FormatInt(f.Revision, 10) + `"`
+}
+
+func setFeatureETag(c *gin.Context, f *model.Feature) {
+	c.Writer.Header().Set(etagHeader, featureETag(f))
+}
+
+// ifMatch returns whether the request's If-Match header, if it has one, matches
+// the feature. Missing features never match, and weak tags are never equal to
+// the feature's strong tag.
+func ifMatch(c *gin.Context, f *model.Feature) bool {
+	header := c.Request.Header.Get(ifMatchHeader)
+	if header == "" {
+		return true
+	}
+	if f == nil {
+		return false
+	}
+	etag := featureETag(f)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeStatus returns the status for an error saving or deleting a feature. A
+// feature changed since it was read fails the request's If-Match precondition,
+// or conflicts with the change if it had none. Features of read-only stores
+// cannot be changed through the API at all.
+func writeStatus(c *gin.Context, err error) int {
+	if err == store.ErrReadOnly {
+		return http.StatusMethodNotAllowed
+	}
+	if err != store.ErrConflict {
+		return http.StatusInternalServerError
+	}
+	if c.Request.Header.Get(ifMatchHeader) != "" {
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusConflict
+}
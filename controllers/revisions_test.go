@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/context"
+	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/robzienert/lever/store/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	f := &model.Feature{Key: "one", Revision: 3}
+
+	cases := []struct {
+		header   string
+		feature  *model.Feature
+		expected bool
+	}{
+		{"", nil, true},
+		{"", f, true},
+		{`"3"`, f, true},
+		{`"2"`, f, false},
+		{`W/"3"`, f, false},
+		{`"1", "3"`, f, true},
+		{"*", f, true},
+		{"*", nil, false},
+		{`"0"`, nil, false},
+	}
+	for i, c := range cases {
+		ctx := &gin.Context{Request: &http.Request{Header: http.Header{}}}
+		if c.header != "" {
+			ctx.Request.Header.Set(ifMatchHeader, c.header)
+		}
+		assert.Equal(t, c.expected, ifMatch(ctx, c.feature), fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func (suite *FeaturesTestSuite) serveIfMatch(store store.Store, method string, endpoint string, ifMatch string, endpointFn func(router *gin.Engine), body string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(context.SetStore(store))
+	endpointFn(router)
+
+	req, _ := http.NewRequest(method, endpoint, strings.NewReader(body))
+	if ifMatch != "" {
+		req.Header.Set(ifMatchHeader, ifMatch)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func (suite *FeaturesTestSuite) TestFeatureRevisions() {
+	memStore := memory.Load()
+	put := func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}
+	body := jsonString(model.Feature{Key: "one", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{}})
+
+	resp := suite.serveIfMatch(memStore, "PUT", "/features/one", "", put, body)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Equal(suite.T(), `"1"`, resp.Header().Get(etagHeader))
+
+	featureResp := &api.FeatureResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), featureResp))
+	assert.EqualValues(suite.T(), 1, featureResp.Feature.Revision)
+
+	resp = suite.serveIfMatch(memStore, "GET", "/features/one", "", func(router *gin.Engine) {
+		router.GET("/features/:key", GetFeature)
+	}, "")
+	assert.Equal(suite.T(), `"1"`, resp.Header().Get(etagHeader))
+
+	resp = suite.serveIfMatch(memStore, "PUT", "/features/one", `"1"`, put, body)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.Equal(suite.T(), `"2"`, resp.Header().Get(etagHeader))
+
+	resp = suite.serveIfMatch(memStore, "PUT", "/features/one", `"1"`, put, body)
+	assert.Equal(suite.T(), http.StatusPreconditionFailed, resp.Code, "stale If-Match")
+
+	resp = suite.serveIfMatch(memStore, "PUT", "/features/two", "*", put,
+		jsonString(model.Feature{Key: "two", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{}}))
+	assert.Equal(suite.T(), http.StatusPreconditionFailed, resp.Code, "If-Match on missing feature")
+
+	del := func(router *gin.Engine) {
+		router.DELETE("/features/:key", DeleteFeature)
+	}
+	resp = suite.serveIfMatch(memStore, "DELETE", "/features/one", `"1"`, del, "")
+	assert.Equal(suite.T(), http.StatusPreconditionFailed, resp.Code, "stale If-Match on delete")
+
+	resp = suite.serveIfMatch(memStore, "DELETE", "/features/one", `"2"`, del, "")
+	assert.Equal(suite.T(), http.StatusNoContent, resp.Code)
+}
+
+func (suite *FeaturesTestSuite) TestFeatureRevisions_StoreConflict() {
+	mockStore := mock.LoadFeatureStore(&mock.FeatureStore{
+		GetFn: func(ns string, key string) (*model.Feature, error) {
+			return &model.Feature{Key: key, Gate: &model.Gate{}, Revision: 1}, nil
+		},
+		GetAllFn: func() ([]*model.Feature, error) {
+			return nil, nil
+		},
+		UpsertFn: func(feature *model.Feature) error {
+			return store.ErrConflict
+		},
+		DeleteFn: func(feature *model.Feature) error {
+			return store.ErrConflict
+		},
+	})
+	body := jsonString(model.Feature{Key: "one", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{}})
+	put := func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}
+
+	resp := suite.serveIfMatch(mockStore, "PUT", "/features/one", "", put, body)
+	assert.Equal(suite.T(), http.StatusConflict, resp.Code)
+
+	resp = suite.serveIfMatch(mockStore, "PUT", "/features/one", `"1"`, put, body)
+	assert.Equal(suite.T(), http.StatusPreconditionFailed, resp.Code)
+
+	resp = suite.serveIfMatch(mockStore, "DELETE", "/features/one", "", func(router *gin.Engine) {
+		router.DELETE("/features/:key", DeleteFeature)
+	}, "")
+	assert.Equal(suite.T(), http.StatusConflict, resp.Code)
+}
+
+func (suite *FeaturesTestSuite) TestFeatureRevisions_ReadOnly() {
+	mockStore := mock.LoadFeatureStore(&mock.FeatureStore{
+		GetFn: func(ns string, key string) (*model.Feature, error) {
+			return &model.Feature{Key: key, Gate: &model.Gate{}, Revision: 1}, nil
+		},
+		GetAllFn: func() ([]*model.Feature, error) {
+			return nil, nil
+		},
+		UpsertFn: func(feature *model.Feature) error {
+			return store.ErrReadOnly
+		},
+		DeleteFn: func(feature *model.Feature) error {
+			return store.ErrReadOnly
+		},
+	})
+
+	resp := suite.serveIfMatch(mockStore, "PUT", "/features/one", "", func(router *gin.Engine) {
+		router.PUT("/features/:key", PutFeature)
+	}, jsonString(model.Feature{Key: "one", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{}}))
+	assert.Equal(suite.T(), http.StatusMethodNotAllowed, resp.Code)
+
+	resp = suite.serveIfMatch(mockStore, "DELETE", "/features/one", "", func(router *gin.Engine) {
+		router.DELETE("/features/:key", DeleteFeature)
+	}, "")
+	assert.Equal(suite.T(), http.StatusMethodNotAllowed, resp.Code)
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/session"
+	"github.com/robzienert/lever/store"
+)
+
+// PauseRollout holds a feature's running rollout plan at its current step.
+func PauseRollout(c *gin.Context) {
+	updateRollout(c, "pause rollout", func(r *model.Rollout, now time.Time) error {
+		return r.Pause()
+	})
+}
+
+// ResumeRollout continues a feature's paused rollout plan. The next step is
+// taken one full interval after resuming.
+func ResumeRollout(c *gin.Context) {
+	updateRollout(c, "resume rollout", func(r *model.Rollout, now time.Time) error {
+		return r.Resume(now)
+	})
+}
+
+// AbortRollout stops a feature's rollout plan and sets the gate's actor percent
+// back to 0.
+func AbortRollout(c *gin.Context) {
+	updateRollout(c, "abort rollout", func(r *model.Rollout, now time.Time) error {
+		return r.Abort()
+	})
+}
+
+func updateRollout(c *gin.Context, action string, fn func(r *model.Rollout, now time.Time) error) {
+	feature, err := store.GetFeature(c, c.Query(namespaceQuery), c.Param(keyParam))
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if feature == nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	if !ifMatch(c, feature) {
+		c.AbortWithError(http.StatusPreconditionFailed, errPreconditionFailed)
+		return
+	}
+	if feature.Rollout == nil {
+		c.AbortWithError(http.StatusNotFound, errors.New("feature has no rollout plan"))
+		return
+	}
+
+	now := time.Now().UTC()
+	next := feature.Copy()
+	if err = fn(next.Rollout, now); err != nil {
+		c.AbortWithError(http.StatusConflict, err)
+		return
+	}
+	next.Gate.ActorPercent = next.Rollout.Percent()
+	next.LastUpdated = now
+
+	if err = store.UpsertFeature(c, next); err != nil {
+		c.AbortWithError(writeStatus(c, err), err)
+		return
+	}
+
+	fields := feature.Diff(next)
+	fields["key"] = feature.Key
+	fields["ns"] = feature.Namespace
+	breadcrumb := model.NewBreadcrumb(action, session.AuditActor(c)).WithFields(fields)
+
+	go saveBreadcrumb(c.Copy(), breadcrumb)
+
+	setFeatureETag(c, next)
+	c.IndentedJSON(http.StatusOK, api.FeatureResponse{Feature: next})
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/context"
+	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RolloutsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RolloutsTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *RolloutsTestSuite) serveRollout(s store.Store, action string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(context.SetStore(s))
+	router.POST("/features/:key/rollout/"+action, handler)
+
+	req, _ := http.NewRequest("POST", "/features/foo/rollout/"+action, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func (suite *RolloutsTestSuite) runningFeature() *model.Feature {
+	next := time.Now().Add(time.Hour)
+	return &model.Feature{
+		Key:  "foo",
+		Gate: &model.Gate{Actors: []string{"one"}, ActorPercent: 5},
+		Rollout: &model.Rollout{
+			Steps:      []int{1, 5, 25},
+			Interval:   "24h",
+			State:      model.RolloutRunningState,
+			Step:       1,
+			NextStepAt: &next,
+		},
+	}
+}
+
+func (suite *RolloutsTestSuite) TestRollout_NotFound() {
+	memStore := memory.Load()
+	resp := suite.serveRollout(memStore, "pause", PauseRollout)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)
+
+	memStore.Features().Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{}})
+	resp = suite.serveRollout(memStore, "pause", PauseRollout)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)
+}
+
+func (suite *RolloutsTestSuite) TestRollout_PauseResume() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(suite.runningFeature())
+
+	resp := suite.serveRollout(memStore, "pause", PauseRollout)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	featureResp := &api.FeatureResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), featureResp))
+	assert.Equal(suite.T(), model.RolloutPausedState, featureResp.Feature.Rollout.State)
+	assert.Nil(suite.T(), featureResp.Feature.Rollout.NextStepAt)
+	assert.Equal(suite.T(), 5, featureResp.Feature.Gate.ActorPercent)
+
+	resp = suite.serveRollout(memStore, "pause", PauseRollout)
+	assert.Equal(suite.T(), http.StatusConflict, resp.Code)
+
+	resp = suite.serveRollout(memStore, "resume", ResumeRollout)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	stored, _ := memStore.Features().Get("foo")
+	assert.Equal(suite.T(), model.RolloutRunningState, stored.Rollout.State)
+	assert.Equal(suite.T(), 1, stored.Rollout.Step)
+	assert.NotNil(suite.T(), stored.Rollout.NextStepAt)
+}
+
+func (suite *RolloutsTestSuite) TestRollout_Abort() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(suite.runningFeature())
+
+	resp := suite.serveRollout(memStore, "abort", AbortRollout)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	stored, _ := memStore.Features().Get("foo")
+	assert.Equal(suite.T(), model.RolloutAbortedState, stored.Rollout.State)
+	assert.Equal(suite.T(), 0, stored.Gate.ActorPercent)
+
+	resp = suite.serveRollout(memStore, "resume", ResumeRollout)
+	assert.Equal(suite.T(), http.StatusConflict, resp.Code)
+
+	// The listed actors are disabled, rather than all enabled by an actors gate.
+	router := gin.New()
+	router.Use(context.SetStore(memStore))
+	router.GET("/features/:key/state", GetFeatureState)
+	req, _ := http.NewRequest("GET", "/features/foo/state?actors=one", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	stateResp := &api.FeatureState{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), stateResp))
+	assert.False(suite.T(), stateResp.Enabled)
+}
+
+func (suite *RolloutsTestSuite) TestRollout_StaleIfMatch() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(suite.runningFeature())
+	router := gin.New()
+	router.Use(context.SetStore(memStore))
+	router.POST("/features/:key/rollout/pause", PauseRollout)
+
+	cases := []struct {
+		ifMatch string
+		code    int
+	}{
+		{`"0"`, http.StatusPreconditionFailed},
+		{`"1"`, http.StatusOK},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("POST", "/features/foo/rollout/pause", nil)
+		req.Header.Set(ifMatchHeader, c.ifMatch)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(suite.T(), c.code, resp.Code, c.ifMatch)
+	}
+	stored, _ := memStore.Features().Get("foo")
+	assert.Equal(suite.T(), model.RolloutPausedState, stored.Rollout.State)
+}
+
+func TestRolloutsTestSuite(t *testing.T) {
+	suite.Run(t, new(RolloutsTestSuite))
+}
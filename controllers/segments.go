@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/session"
+	"github.com/robzienert/lever/shared/strutil"
+	"github.com/robzienert/lever/store"
+)
+
+const nameParam = "name"
+
+// GetAllSegments returns all segments.
+func GetAllSegments(c *gin.Context) {
+	segments, err := store.GetSegmentList(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if segments == nil {
+		segments = make([]*model.Segment, 0)
+	}
+
+	c.IndentedJSON(http.StatusOK, api.GetSegmentListResponse{Segments: segments})
+}
+
+// GetSegment returns an individual segment by name.
+func GetSegment(c *gin.Context) {
+	segment, err := store.GetSegment(c, c.Param(nameParam))
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if segment == nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, api.SegmentResponse{Segment: segment})
+}
+
+// PutSegment idempotently upserts a segment. Every feature referencing the
+// segment picks up the change on its next evaluation.
+func PutSegment(c *gin.Context) {
+	var in model.Segment
+	if err := c.BindJSON(&in); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if c.Param(nameParam) != in.Name {
+		c.AbortWithError(http.StatusBadRequest, errors.New("name URL param does not match Segment name in body"))
+		return
+	}
+
+	if err := in.Validate(); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	segment, err := store.GetSegment(c, in.Name)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var breadcrumb *model.Breadcrumb
+	now := time.Now().UTC()
+	if segment == nil {
+		segment = &in
+		segment.DateCreated = now
+
+		breadcrumb = model.NewBreadcrumb("create segment", session.AuditActor(c)).WithField("name", segment.Name)
+	} else {
+		fields := segment.Diff(&in)
+		fields["name"] = segment.Name
+
+		next := in
+		next.DateCreated = segment.DateCreated
+		segment = &next
+
+		breadcrumb = model.NewBreadcrumb("update segment", session.AuditActor(c)).WithFields(fields)
+	}
+	segment.LastUpdated = now
+
+	if err = store.UpsertSegment(c, segment); err != nil {
+		c.AbortWithError(writeStatus(c, err), err)
+		return
+	}
+
+	go saveBreadcrumb(c.Copy(), breadcrumb)
+
+	c.IndentedJSON(http.StatusOK, api.SegmentResponse{Segment: segment})
+}
+
+// DeleteSegment removes a segment from the service. Segments that features
+// reference are only removed when the "force" query param is "true".
+func DeleteSegment(c *gin.Context) {
+	segment, err := store.GetSegment(c, c.Param(nameParam))
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if segment == nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	features, err := store.GetAllFeatures(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	var dependents []string
+	for _, f := range features {
+		if strutil.StringInSlice(segment.Name, f.SegmentNames()) {
+			dependents = append(dependents, f.ID())
+		}
+	}
+	if len(dependents) > 0 && c.Query(forceQuery) != "true" {
+		c.AbortWithError(http.StatusConflict, fmt.Errorf("features use segment %s: %s", segment.Name, strings.Join(dependents, ", ")))
+		return
+	}
+
+	if err = store.DeleteSegment(c, segment); err != nil {
+		c.AbortWithError(writeStatus(c, err), err)
+		return
+	}
+
+	breadcrumb := model.NewBreadcrumb("delete segment", session.AuditActor(c)).WithField("name", segment.Name)
+	if len(dependents) > 0 {
+		breadcrumb.WithField("dependents", strings.Join(dependents, ","))
+	}
+
+	go saveBreadcrumb(c.Copy(), breadcrumb)
+
+	c.Writer.WriteHeader(http.StatusNoContent)
+}
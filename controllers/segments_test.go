@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/context"
+	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/robzienert/lever/store/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SegmentsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SegmentsTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *SegmentsTestSuite) serveEndpoint(store store.Store, method string, endpoint string, body io.Reader) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(context.SetStore(store))
+	router.GET("/segments", GetAllSegments)
+	router.GET("/segments/:name", GetSegment)
+	router.PUT("/segments/:name", PutSegment)
+	router.DELETE("/segments/:name", DeleteSegment)
+
+	req, _ := http.NewRequest(method, endpoint, body)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func (suite *SegmentsTestSuite) TestSegmentGetAll_Empty() {
+	resp := suite.serveEndpoint(memory.Load(), "GET", "/segments", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	assert.JSONEq(suite.T(), jsonString(api.GetSegmentListResponse{Segments: []*model.Segment{}}), resp.Body.String())
+}
+
+func (suite *SegmentsTestSuite) TestSegmentGetAll_StoreError() {
+	mockStore := mock.LoadSegmentStore(&mock.SegmentStore{
+		GetListFn: func() ([]*model.Segment, error) {
+			return nil, errors.New("oh no")
+		},
+	})
+	resp := suite.serveEndpoint(mockStore, "GET", "/segments", nil)
+
+	assert.Equal(suite.T(), http.StatusInternalServerError, resp.Code)
+}
+
+func (suite *SegmentsTestSuite) TestSegmentGet_NotFound() {
+	resp := suite.serveEndpoint(memory.Load(), "GET", "/segments/beta", nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)
+}
+
+func (suite *SegmentsTestSuite) TestSegmentPut_NonmatchingName() {
+	resp := suite.serveEndpoint(memory.Load(), "PUT", "/segments/beta", strings.NewReader(`{"name":"alpha","actors":["one"]}`))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *SegmentsTestSuite) TestSegmentPut_Invalid() {
+	resp := suite.serveEndpoint(memory.Load(), "PUT", "/segments/beta", strings.NewReader(`{"name":"beta"}`))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (suite *SegmentsTestSuite) TestSegmentPut_OK() {
+	memStore := memory.Load()
+
+	resp := suite.serveEndpoint(memStore, "PUT", "/segments/beta", strings.NewReader(`{"name":"beta","actors":["one"]}`))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	created, _ := memStore.Segments().Get("beta")
+	assert.Equal(suite.T(), []string{"one"}, created.Actors)
+
+	resp = suite.serveEndpoint(memStore, "PUT", "/segments/beta", strings.NewReader(`{"name":"beta","actors":["one","two"]}`))
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	segmentResp := &api.SegmentResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), segmentResp))
+	assert.Equal(suite.T(), []string{"one", "two"}, segmentResp.Segment.Actors)
+	assert.Equal(suite.T(), created.DateCreated.Unix(), segmentResp.Segment.DateCreated.Unix())
+
+	// Breadcrumbs are saved asynchronously.
+	time.Sleep(50 * time.Millisecond)
+	breadcrumbs, _ := memStore.Breadcrumbs().GetList()
+	actions := make(map[string]*model.Breadcrumb)
+	for _, b := range breadcrumbs {
+		actions[b.Action] = b
+	}
+	assert.Contains(suite.T(), actions, "create segment")
+	if assert.Contains(suite.T(), actions, "update segment") {
+		assert.Equal(suite.T(), "one -> one,two", actions["update segment"].Fields["actors"])
+		assert.Equal(suite.T(), "beta", actions["update segment"].Fields["name"])
+	}
+}
+
+func (suite *SegmentsTestSuite) TestSegmentPut_ReadOnly() {
+	mockStore := mock.LoadSegmentStore(&mock.SegmentStore{
+		GetFn: func(name string) (*model.Segment, error) {
+			return nil, nil
+		},
+		UpsertFn: func(segment *model.Segment) error {
+			return store.ErrReadOnly
+		},
+	})
+	resp := suite.serveEndpoint(mockStore, "PUT", "/segments/beta", strings.NewReader(`{"name":"beta","actors":["one"]}`))
+
+	assert.Equal(suite.T(), http.StatusMethodNotAllowed, resp.Code)
+}
+
+func (suite *SegmentsTestSuite) TestSegmentDelete_NotFound() {
+	resp := suite.serveEndpoint(memory.Load(), "DELETE", "/segments/beta", nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)
+}
+
+func (suite *SegmentsTestSuite) TestSegmentDelete_InUse() {
+	memStore := memory.Load()
+	memStore.Segments().Upsert(&model.Segment{Name: "beta", Actors: []string{"one"}})
+	memStore.Features().Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{Segments: []string{"beta"}}})
+
+	resp := suite.serveEndpoint(memStore, "DELETE", "/segments/beta", nil)
+	assert.Equal(suite.T(), http.StatusConflict, resp.Code)
+
+	resp = suite.serveEndpoint(memStore, "DELETE", "/segments/beta?force=true", nil)
+	assert.Equal(suite.T(), http.StatusNoContent, resp.Code)
+
+	segments, _ := memStore.Segments().GetList()
+	assert.Len(suite.T(), segments, 0)
+}
+
+func TestSegmentsTestSuite(t *testing.T) {
+	suite.Run(t, new(SegmentsTestSuite))
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+)
+
+// GetUsage returns how often each feature has been evaluated, by OAuth client
+// and outcome. Features that were never evaluated are included without usage,
+// so that unused features can be found. Servers add their counts to the store
+// periodically, so the latest evaluations may be missing.
+func GetUsage(c *gin.Context) {
+	features, err := store.GetAllFeatures(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	usage, err := store.GetUsageList(c)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	byFeature := make(map[string][]*model.Usage)
+	for _, u := range usage {
+		byFeature[u.ID()] = append(byFeature[u.ID()], u)
+	}
+
+	resp := api.GetUsageResponse{Features: make([]api.FeatureUsage, 0, len(features))}
+	for _, f := range features {
+		fu := api.FeatureUsage{
+			Namespace: f.Namespace,
+			Key:       f.Key,
+			Usage:     byFeature[f.ID()],
+		}
+		if fu.Usage == nil {
+			fu.Usage = make([]*model.Usage, 0)
+		}
+		sort.Sort(byClientOutcome(fu.Usage))
+		for _, u := range fu.Usage {
+			fu.Evaluations += u.Count
+			if fu.LastEvaluated == nil || u.LastEvaluated.After(*fu.LastEvaluated) {
+				lastEvaluated := u.LastEvaluated
+				fu.LastEvaluated = &lastEvaluated
+			}
+		}
+		resp.Features = append(resp.Features, fu)
+	}
+	sort.Sort(byFeatureID(resp.Features))
+
+	c.IndentedJSON(http.StatusOK, resp)
+}
+
+type byFeatureID []api.FeatureUsage
+
+func (s byFeatureID) Len() int      { return len(s) }
+func (s byFeatureID) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byFeatureID) Less(i, j int) bool {
+	if s[i].Namespace != s[j].Namespace {
+		return s[i].Namespace < s[j].Namespace
+	}
+	return s[i].Key < s[j].Key
+}
+
+type byClientOutcome []*model.Usage
+
+func (s byClientOutcome) Len() int      { return len(s) }
+func (s byClientOutcome) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byClientOutcome) Less(i, j int) bool {
+	if s[i].ClientID != s[j].ClientID {
+		return s[i].ClientID < s[j].ClientID
+	}
+	return s[i].Outcome < s[j].Outcome
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robzienert/lever/api"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/router/middleware/context"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/robzienert/lever/usage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UsageTestSuite struct {
+	suite.Suite
+}
+
+func (suite *UsageTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *UsageTestSuite) TestUsage_Tracked() {
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{Key: "foo", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Actors: []string{"one"}}})
+	memStore.Features().Upsert(&model.Feature{Key: "unused", Type: "java.lang.Boolean", Value: "true", Gate: &model.Gate{Value: "true"}})
+	tracker := usage.NewTracker(memStore, time.Hour)
+
+	router := gin.New()
+	router.Use(context.SetStore(memStore))
+	router.Use(context.SetUsageTracker(tracker))
+	router.GET("/features/:key/state", GetFeatureState)
+	router.GET("/usage", GetUsage)
+
+	for _, actor := range []string{"one", "two", "three"} {
+		req, _ := http.NewRequest("GET", "/features/foo/state?actors="+actor, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+	}
+	tracker.Flush()
+
+	req, _ := http.NewRequest("GET", "/usage", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	usageResp := api.GetUsageResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &usageResp))
+	if assert.Len(suite.T(), usageResp.Features, 2) {
+		foo := usageResp.Features[0]
+		assert.Equal(suite.T(), "foo", foo.Key)
+		assert.Equal(suite.T(), int64(3), foo.Evaluations)
+		assert.NotNil(suite.T(), foo.LastEvaluated)
+		if assert.Len(suite.T(), foo.Usage, 2) {
+			assert.Equal(suite.T(), model.DisabledOutcome, foo.Usage[0].Outcome)
+			assert.Equal(suite.T(), int64(2), foo.Usage[0].Count)
+			assert.Equal(suite.T(), model.EnabledOutcome, foo.Usage[1].Outcome)
+			assert.Equal(suite.T(), int64(1), foo.Usage[1].Count)
+		}
+
+		unused := usageResp.Features[1]
+		assert.Equal(suite.T(), "unused", unused.Key)
+		assert.Zero(suite.T(), unused.Evaluations)
+		assert.Nil(suite.T(), unused.LastEvaluated)
+		assert.Empty(suite.T(), unused.Usage)
+	}
+}
+
+func TestUsageTestSuite(t *testing.T) {
+	suite.Run(t, new(UsageTestSuite))
+}
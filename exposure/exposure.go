@@ -0,0 +1,176 @@
+package exposure
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+	"github.com/Sirupsen/logrus"
+	"github.com/spaolacci/murmur3"
+	"golang.org/x/net/context"
+)
+
+// Key represents the context value for the exposure exporter.
+const Key = "exposure"
+
+// FromContext returns the exposure exporter from net.Context, or nil if
+// exposures are not exported.
+func FromContext(c context.Context) *Exporter {
+	v := c.Value(Key)
+	if v != nil {
+		return v.(*Exporter)
+	}
+	return nil
+}
+
+// Event records that an actor was exposed to a feature, and to the variant it
+// was assigned, if any.
+type Event struct {
+	Namespace   string    `json:"namespace,omitempty"`
+	Key         string    `json:"key"`
+	Actor       string    `json:"actor"`
+	Enabled     bool      `json:"enabled"`
+	Variant     string    `json:"variant,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	ClientID    string    `json:"clientId,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Sink receives batches of exposure events. Sinks are only called from a
+// single goroutine, and must not keep a batch after writing it.
+type Sink interface {
+	Write([]Event) error
+	Close() error
+}
+
+// ExporterSpec configures an Exporter.
+//
+// SamplePercent is the percentage of actors whose exposures are exported.
+// Actors are sampled per feature, so a sampled actor's exposures to a feature
+// are all exported.
+type ExporterSpec struct {
+	Sink          Sink
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+	SamplePercent int
+	StatsD        *statsd.Client
+}
+
+// Exporter buffers exposure events and writes them to its sink in batches in
+// the background. When the buffer is full, because the sink is slow or
+// failing, new events are dropped rather than delaying evaluations.
+type Exporter struct {
+	spec    ExporterSpec
+	events  chan Event
+	dropped uint64
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewExporter creates an exporter for the spec.
+func NewExporter(spec ExporterSpec) *Exporter {
+	return &Exporter{
+		spec:    spec,
+		events:  make(chan Event, spec.BufferSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Expose queues the event if its actor is sampled. It never blocks.
+func (e *Exporter) Expose(event Event) {
+	if !e.sampled(event) {
+		return
+	}
+	select {
+	case e.events <- event:
+	default:
+		atomic.AddUint64(&e.dropped, 1)
+		if e.spec.StatsD != nil {
+			e.spec.StatsD.Incr("exposure.dropped", nil, 1)
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because the buffer was full.
+func (e *Exporter) Dropped() uint64 {
+	return atomic.LoadUint64(&e.dropped)
+}
+
+func (e *Exporter) sampled(event Event) bool {
+	if e.spec.SamplePercent >= 100 {
+		return true
+	}
+	id := event.Key
+	if event.Namespace != "" {
+		id = event.Namespace + "/" + id
+	}
+	bucket := murmur3.Sum32([]byte(id+":exposure:"+event.Actor)) % 100
+	return int(bucket) < e.spec.SamplePercent
+}
+
+// Start writes queued events in the background until the exporter is closed.
+func (e *Exporter) Start() {
+	go func() {
+		defer close(e.stopped)
+		ticker := time.NewTicker(e.spec.FlushInterval)
+		defer ticker.Stop()
+
+		batch := make([]Event, 0, e.spec.BatchSize)
+		for {
+			select {
+			case event := <-e.events:
+				batch = e.add(batch, event)
+			case <-ticker.C:
+				batch = e.write(batch)
+			case <-e.done:
+				for {
+					select {
+					case event := <-e.events:
+						batch = e.add(batch, event)
+					default:
+						e.write(batch)
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close writes the queued events and closes the sink.
+func (e *Exporter) Close() {
+	close(e.done)
+	<-e.stopped
+	if err := e.spec.Sink.Close(); err != nil {
+		logrus.WithField("err", err).Error("Could not close exposure sink")
+	}
+}
+
+// add appends the event to the batch, writing the batch once it is full.
+func (e *Exporter) add(batch []Event, event Event) []Event {
+	batch = append(batch, event)
+	if len(batch) >= e.spec.BatchSize {
+		return e.write(batch)
+	}
+	return batch
+}
+
+// write sends the batch to the sink and returns an empty batch. Batches that
+// fail are dropped, so that a failing sink does not grow the memory in use.
+func (e *Exporter) write(batch []Event) []Event {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := e.spec.Sink.Write(batch); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err":    err,
+			"events": len(batch),
+		}).Error("Could not write exposure events")
+		if e.spec.StatsD != nil {
+			e.spec.StatsD.Count("exposure.failed", int64(len(batch)), nil, 1)
+		}
+	}
+	return batch[:0]
+}
@@ -0,0 +1,84 @@
+package exposure
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSink struct {
+	lock    sync.Mutex
+	batches [][]Event
+	closed  bool
+	block   chan struct{}
+}
+
+func (s *recordingSink) Write(events []Event) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.batches = append(s.batches, append([]Event(nil), events...))
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestExporter_Batches(t *testing.T) {
+	sink := &recordingSink{}
+	e := NewExporter(ExporterSpec{Sink: sink, BufferSize: 10, BatchSize: 2, FlushInterval: time.Hour, SamplePercent: 100})
+	e.Start()
+	for _, actor := range []string{"one", "two", "three"} {
+		e.Expose(Event{Key: "foo", Actor: actor})
+	}
+	e.Close()
+
+	assert.True(t, sink.closed)
+	if assert.Len(t, sink.batches, 2) {
+		assert.Len(t, sink.batches[0], 2)
+		assert.Equal(t, []Event{{Key: "foo", Actor: "three"}}, sink.batches[1])
+	}
+}
+
+func TestExporter_DropsWhenFull(t *testing.T) {
+	sink := &recordingSink{block: make(chan struct{})}
+	e := NewExporter(ExporterSpec{Sink: sink, BufferSize: 2, BatchSize: 1, FlushInterval: time.Hour, SamplePercent: 100})
+	e.Start()
+
+	// The first event is taken from the buffer and blocks in the sink, then
+	// two more fill the buffer.
+	e.Expose(Event{Key: "foo", Actor: "one"})
+	for len(e.events) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for _, actor := range []string{"two", "three", "four", "five"} {
+		e.Expose(Event{Key: "foo", Actor: actor})
+	}
+	assert.Equal(t, uint64(2), e.Dropped())
+
+	close(sink.block)
+	e.Close()
+	assert.Len(t, sink.batches, 3)
+}
+
+func TestExporter_Sampling(t *testing.T) {
+	e := NewExporter(ExporterSpec{SamplePercent: 0})
+	assert.False(t, e.sampled(Event{Key: "foo", Actor: "one"}))
+
+	e.spec.SamplePercent = 50
+	sampled := 0
+	for i := 0; i < 1000; i++ {
+		event := Event{Namespace: "mobile", Key: "foo", Actor: string(rune('a'+i%26)) + string(rune('a'+i/26))}
+		if e.sampled(event) {
+			sampled++
+		}
+		assert.Equal(t, e.sampled(event), e.sampled(event), "sampling is not consistent")
+	}
+	assert.InDelta(t, 500, sampled, 100)
+}
@@ -0,0 +1,85 @@
+package exposure
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// FileSink writes events to a local file as newline-delimited JSON. Once the
+// file reaches its maximum size, it is renamed with a timestamp suffix and a
+// new file is started.
+type FileSink struct {
+	path    string
+	maxSize int64
+	file    *os.File
+	size    int64
+	clock   func() time.Time
+}
+
+// NewFileSink opens the file at path for appending. A maxSize of 0 disables
+// rotation.
+func NewFileSink(path string, maxSize int64) (*FileSink, error) {
+	s := &FileSink{path: path, maxSize: maxSize, clock: time.Now}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+// Write appends the events to the file, rotating it first if it is full.
+func (s *FileSink) Write(events []Event) error {
+	if s.maxSize > 0 && s.size >= s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	// The size counts every byte written, as bufio writes large events straight
+	// to the file without buffering them.
+	w := bufio.NewWriter(s.file)
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		n, err := w.Write(append(data, '\n'))
+		s.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	rotated := s.path + "." + s.clock().UTC().Format("20060102T150405.000000000")
+	if err := os.Rename(s.path, rotated); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
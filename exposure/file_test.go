@@ -0,0 +1,72 @@
+package exposure
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSink_Rotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lever-exposure")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "exposures.ndjson")
+	sink, err := NewFileSink(path, 10)
+	assert.NoError(t, err)
+	sink.clock = func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) }
+
+	assert.NoError(t, sink.Write([]Event{{Key: "foo", Actor: "one"}, {Key: "foo", Actor: "two"}}))
+	assert.NoError(t, sink.Write([]Event{{Key: "foo", Actor: "three"}}))
+	assert.NoError(t, sink.Close())
+
+	rotated := readEvents(t, path+".20261018T120000.000000000")
+	assert.Len(t, rotated, 2)
+	assert.Equal(t, "two", rotated[1].Actor)
+	current := readEvents(t, path)
+	assert.Equal(t, []Event{{Key: "foo", Actor: "three"}}, current)
+}
+
+func TestFileSink_RotateLargeEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lever-exposure")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "exposures.ndjson")
+	sink, err := NewFileSink(path, 4096)
+	assert.NoError(t, err)
+	sink.clock = func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) }
+
+	// Events larger than the write buffer still count towards the size.
+	large := Event{Key: "foo", Actor: strings.Repeat("a", 8192)}
+	assert.NoError(t, sink.Write([]Event{large}))
+	assert.NoError(t, sink.Write([]Event{{Key: "foo", Actor: "two"}}))
+	assert.NoError(t, sink.Close())
+
+	assert.Equal(t, []Event{large}, readEvents(t, path+".20261018T120000.000000000"))
+	assert.Equal(t, []Event{{Key: "foo", Actor: "two"}}, readEvents(t, path))
+}
+
+func readEvents(t *testing.T, path string) []Event {
+	f, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	return events
+}
@@ -0,0 +1,43 @@
+package exposure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPSink posts each batch of events to a URL as a JSON array.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink creates a sink posting to the URL. Requests that take longer than
+// the timeout fail.
+func NewHTTPSink(url string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Write posts the events. Responses other than 2xx are errors.
+func (s *HTTPSink) Write(events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("exposure sink responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// Close does nothing, as requests are not kept open between batches.
+func (s *HTTPSink) Close() error {
+	return nil
+}
@@ -0,0 +1,30 @@
+package exposure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSink_Write(t *testing.T) {
+	var received []Event
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, time.Second)
+	events := []Event{{Key: "foo", Actor: "one", Enabled: true, Variant: "treatment"}}
+	assert.NoError(t, sink.Write(events))
+	assert.Equal(t, events, received)
+
+	status = http.StatusServiceUnavailable
+	assert.Error(t, sink.Write(events))
+}
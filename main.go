@@ -5,14 +5,22 @@ import (
 	"github.com/robzienert/gin-middleware/header"
 	"github.com/robzienert/gin-middleware/oauth"
 	"github.com/robzienert/http-healthcheck"
+	"github.com/robzienert/lever/exposure"
 	"github.com/robzienert/lever/metrics"
+	"github.com/robzienert/lever/rollout"
 	"github.com/robzienert/lever/router"
+	"github.com/robzienert/lever/router/middleware/clientip"
 	"github.com/robzienert/lever/router/middleware/context"
 	"github.com/robzienert/lever/shared/config"
 	"github.com/robzienert/lever/shared/server"
 	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/store/bolt"
+	"github.com/robzienert/lever/store/cache"
 	"github.com/robzienert/lever/store/cql"
+	"github.com/robzienert/lever/store/file"
 	"github.com/robzienert/lever/store/memory"
+	"github.com/robzienert/lever/store/sql"
+	"github.com/robzienert/lever/usage"
 	"github.com/spf13/viper"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
@@ -42,7 +50,8 @@ func main() {
 	var healthProviders []healthcheck.Provider
 
 	var backendStore store.Store
-	if viper.GetString("store") == "cql" {
+	switch viper.GetString("store") {
+	case "cql":
 		cqlStoreResp, err := cql.Load(cql.StoreSpec{
 			Keyspace:   viper.GetString("cassandra.keyspace"),
 			Hosts:      viper.GetStringSlice("cassandra.hosts"),
@@ -61,9 +70,45 @@ func main() {
 		defer cqlStoreResp.Session.Close()
 		healthProviders = append(healthProviders, cqlStoreResp.HealthProvider)
 		backendStore = cqlStoreResp.Store
-	} else {
+	case "bolt":
+		boltStoreResp, err := bolt.Load(viper.GetString("bolt.path"))
+		if err != nil {
+			logrus.WithField("err", err).Fatal("Error opening Bolt database")
+		}
+		defer boltStoreResp.DB.Close()
+		healthProviders = append(healthProviders, boltStoreResp.HealthProvider)
+		backendStore = boltStoreResp.Store
+	case "sql":
+		sqlStoreResp, err := sql.Load(sql.StoreSpec{
+			Dialect: viper.GetString("sql.dialect"),
+			DSN:     viper.GetString("sql.dsn"),
+		})
+		if err != nil {
+			logrus.WithField("err", err).Fatal("Error running SQL migrations")
+		}
+		defer sqlStoreResp.DB.Close()
+		healthProviders = append(healthProviders, sqlStoreResp.HealthProvider)
+		backendStore = sqlStoreResp.Store
+	case "file":
+		// Breadcrumbs and usage are not durable with feature files.
+		fileStoreResp, err := file.Load(viper.GetString("file.path"), memory.Load(), statsd)
+		if err != nil {
+			logrus.WithField("err", err).Fatal("Error loading feature files")
+		}
+		defer fileStoreResp.Watcher.Close()
+		fileStoreResp.Watcher.Start()
+		healthProviders = append(healthProviders, fileStoreResp.HealthProvider)
+		backendStore = fileStoreResp.Store
+	default:
 		backendStore = memory.Load()
 	}
+	if viper.GetBool("cache.enabled") {
+		backendStore = cache.New(backendStore, cache.Spec{
+			TTL:     viper.GetDuration("cache.ttl"),
+			MaxSize: int64(viper.GetInt("cache.maxSize")),
+			StatsD:  statsd,
+		})
+	}
 
 	healthMonitor := healthcheck.New(healthcheck.DefaultSupervisor, healthProviders...)
 	{
@@ -71,6 +116,32 @@ func main() {
 		healthMonitor.Start()
 	}
 
+	rolloutScheduler := rollout.NewScheduler(backendStore, statsd, viper.GetDuration("rollout.interval"))
+	{
+		defer rolloutScheduler.Close()
+		rolloutScheduler.Start()
+	}
+
+	usageTracker := usage.NewTracker(backendStore, viper.GetDuration("usage.interval"))
+	{
+		defer usageTracker.Close()
+		usageTracker.Start()
+	}
+
+	var exposureExporter *exposure.Exporter
+	if sink := exposureSink(); sink != nil {
+		exposureExporter = exposure.NewExporter(exposure.ExporterSpec{
+			Sink:          sink,
+			BufferSize:    viper.GetInt("exposure.bufferSize"),
+			BatchSize:     viper.GetInt("exposure.batchSize"),
+			FlushInterval: viper.GetDuration("exposure.flushInterval"),
+			SamplePercent: viper.GetInt("exposure.samplePercent"),
+			StatsD:        statsd,
+		})
+		defer exposureExporter.Close()
+		exposureExporter.Start()
+	}
+
 	tokenValidator := oauth.NewSpringSecTokenValidator(
 		oauth.SpringSecTokenValidatorSpec{
 			Host:     viper.GetString("oauth.host"),
@@ -79,6 +150,11 @@ func main() {
 		},
 	)
 
+	trustedProxies, err := clientip.ParseNetworks(viper.GetStringSlice("http.trustedProxies"))
+	if err != nil {
+		logrus.WithField("err", err).Fatal("Invalid trusted proxies")
+	}
+
 	server := server.Load(viper.GetString("http.addr"), viper.GetString("http.cert"), viper.GetString("http.key"))
 	server.Run(router.Load(
 		tokenValidator,
@@ -86,5 +162,24 @@ func main() {
 		context.SetStatsD(statsd),
 		context.SetHealthMonitor(healthMonitor),
 		context.SetStore(backendStore),
+		context.SetUsageTracker(usageTracker),
+		context.SetExposureExporter(exposureExporter),
+		clientip.Set(trustedProxies),
 	))
 }
+
+// exposureSink creates the configured exposure sink, or nil if exposures are
+// not exported.
+func exposureSink() exposure.Sink {
+	switch viper.GetString("exposure.sink") {
+	case "file":
+		sink, err := exposure.NewFileSink(viper.GetString("exposure.file.path"), int64(viper.GetSizeInBytes("exposure.file.maxSize")))
+		if err != nil {
+			logrus.WithField("err", err).Fatal("Could not open exposure file")
+		}
+		return sink
+	case "http":
+		return exposure.NewHTTPSink(viper.GetString("exposure.http.url"), viper.GetDuration("exposure.http.timeout"))
+	}
+	return nil
+}
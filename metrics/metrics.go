@@ -40,3 +40,11 @@ func New(addr string, bufferLength int) (*statsd.Client, error) {
 
 	return c, nil
 }
+
+// Incr increments a counter. Like WithTiming, it silently does nothing when
+// StatsD is not available.
+func Incr(statsd *statsd.Client, name string, tags ...string) {
+	if statsd != nil {
+		statsd.Incr(name, tags, 1)
+	}
+}
@@ -47,4 +47,157 @@ var cassandraMigrations = []cqlmigrate.Spec{
 		);
     `,
 	},
+	{
+		Name: "2026-10-18-gate_rules",
+		Data: `
+		ALTER TABLE features_namespaced ADD gate_rules varchar;
+		ALTER TABLE features ADD gate_rules varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-gate_seed",
+		Data: `
+		ALTER TABLE features_namespaced ADD gate_seed varchar;
+		ALTER TABLE features ADD gate_seed varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-gate_percent_of_everyone",
+		Data: `
+		ALTER TABLE features_namespaced ADD gate_percent_of_everyone int;
+		ALTER TABLE features ADD gate_percent_of_everyone int;
+		`,
+	},
+	{
+		Name: "2026-10-18-variants",
+		Data: `
+		ALTER TABLE features_namespaced ADD variants varchar;
+		ALTER TABLE features ADD variants varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-gate_schedule",
+		Data: `
+		ALTER TABLE features_namespaced ADD gate_schedule varchar;
+		ALTER TABLE features ADD gate_schedule varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-rollout",
+		Data: `
+		ALTER TABLE features_namespaced ADD rollout varchar;
+		ALTER TABLE features ADD rollout varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-gate_deny_lists",
+		Data: `
+		ALTER TABLE features_namespaced ADD gate_deny_groups list<varchar>;
+		ALTER TABLE features_namespaced ADD gate_deny_actors list<varchar>;
+		ALTER TABLE features ADD gate_deny_groups list<varchar>;
+		ALTER TABLE features ADD gate_deny_actors list<varchar>;
+		`,
+	},
+	{
+		Name: "2026-10-18-prerequisites",
+		Data: `
+		ALTER TABLE features_namespaced ADD prerequisites varchar;
+		ALTER TABLE features ADD prerequisites varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-segments",
+		Data: `
+		CREATE TABLE segments (
+			name varchar,
+			actors list<varchar>,
+			groups list<varchar>,
+			rules varchar,
+			date_created timestamp,
+			last_updated timestamp,
+			PRIMARY KEY(name)
+		);
+
+		ALTER TABLE features_namespaced ADD gate_segments list<varchar>;
+		ALTER TABLE features ADD gate_segments list<varchar>;
+		`,
+	},
+	{
+		Name: "2026-10-18-gate_custom",
+		Data: `
+		ALTER TABLE features_namespaced ADD gate_custom varchar;
+		ALTER TABLE features ADD gate_custom varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-gate_expression",
+		Data: `
+		ALTER TABLE features_namespaced ADD gate_expression varchar;
+		ALTER TABLE features ADD gate_expression varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-schema",
+		Data: `
+		ALTER TABLE features_namespaced ADD schema varchar;
+		ALTER TABLE features ADD schema varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-off_value",
+		Data: `
+		ALTER TABLE features_namespaced ADD off_value varchar;
+		ALTER TABLE features ADD off_value varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-environments",
+		Data: `
+		ALTER TABLE features_namespaced ADD environments varchar;
+		ALTER TABLE features ADD environments varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-gate_cidrs",
+		Data: `
+		ALTER TABLE features_namespaced ADD gate_cidrs list<varchar>;
+		ALTER TABLE features ADD gate_cidrs list<varchar>;
+		`,
+	},
+	{
+		Name: "2026-10-18-lifecycle",
+		Data: `
+		ALTER TABLE features_namespaced ADD lifecycle varchar;
+		ALTER TABLE features ADD lifecycle varchar;
+		`,
+	},
+	{
+		Name: "2026-10-18-usage",
+		Data: `
+		CREATE TABLE usage_counts (
+			key varchar,
+			namespace varchar,
+			client_id varchar,
+			outcome varchar,
+			count counter,
+			PRIMARY KEY(key, namespace, client_id, outcome)
+		);
+
+		CREATE TABLE usage_last_evaluated (
+			key varchar,
+			namespace varchar,
+			client_id varchar,
+			outcome varchar,
+			last_evaluated timestamp,
+			PRIMARY KEY(key, namespace, client_id, outcome)
+		);
+		`,
+	},
+	{
+		Name: "2026-10-18-revision",
+		Data: `
+		ALTER TABLE features_namespaced ADD revision bigint;
+		ALTER TABLE features ADD revision bigint;
+		`,
+	},
 }
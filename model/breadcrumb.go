@@ -5,6 +5,20 @@ import (
 	"time"
 )
 
+// AutomatedActor is the breadcrumb actor for changes the service makes on its
+// own, such as advancing rollout plans.
+const AutomatedActor = "lever"
+
+// CreateFeatureAction is the breadcrumb action for creating a feature.
+const CreateFeatureAction = "create feature"
+
+// UpdateFeatureAction is the breadcrumb action for saving changes to a feature.
+const UpdateFeatureAction = "update feature"
+
+// ReloadFeaturesAction is the breadcrumb action for reloading features that are
+// managed outside of the service, such as in files.
+const ReloadFeaturesAction = "reload features"
+
 // Fields allow defining arbitrary data with a breadcrumb
 type Fields map[string]string
 
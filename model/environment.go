@@ -0,0 +1,89 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EnvironmentGate returns the feature's gate settings in an environment.
+// Environments without settings of their own, including the empty one, use
+// the feature's gate. Environment gates without a seed use the seed of the
+// feature's gate, so that actors are bucketed the same way everywhere.
+func (f *Feature) EnvironmentGate(env string) *Gate {
+	g, ok := f.Environments[env]
+	if !ok || g == nil {
+		return f.rolloutGate()
+	}
+	if g.Seed == "" && f.Gate != nil {
+		seeded := *g
+		seeded.Seed = f.Gate.Seed
+		return &seeded
+	}
+	return g
+}
+
+// InEnvironment returns a copy of the feature as it is seen in an environment,
+// with the environment's gate settings as its gate.
+func (f *Feature) InEnvironment(env string) *Feature {
+	c := f.Copy()
+	c.Gate = f.EnvironmentGate(env)
+	c.Environments = nil
+	return c
+}
+
+// Promote copies the gate settings of one environment to another. The source
+// environment must have settings of its own.
+func (f *Feature) Promote(from string, to string) error {
+	if from == to {
+		return fmt.Errorf("cannot promote environment %s to itself", from)
+	}
+	g, ok := f.Environments[from]
+	if !ok || g == nil {
+		return fmt.Errorf("environment has no gate settings: %s", from)
+	}
+	promoted := *f.EnvironmentGate(from)
+	if f.Environments == nil {
+		f.Environments = make(map[string]*Gate)
+	}
+	f.Environments[to] = &promoted
+	return nil
+}
+
+// SegmentNames returns the names of all segments referred to by the feature's
+// gate and environment gates.
+func (f *Feature) SegmentNames() []string {
+	names := f.Gate.SegmentNames()
+	for _, g := range f.Environments {
+		if g != nil {
+			names = append(names[:len(names):len(names)], g.SegmentNames()...)
+		}
+	}
+	return names
+}
+
+func validateEnvironments(environments map[string]*Gate) error {
+	for env, g := range environments {
+		if env == "" {
+			return fmt.Errorf("environment is missing a name")
+		}
+		if g == nil {
+			return fmt.Errorf("environment has no gate settings: %s", env)
+		}
+		if err := g.Validate(); err != nil {
+			return fmt.Errorf("environment %s: %s", env, err)
+		}
+	}
+	return nil
+}
+
+// gateString returns the gate as JSON, used in audit diffs of environments.
+func gateString(g *Gate) string {
+	if g == nil {
+		return ""
+	}
+	b, err := json.Marshal(g)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
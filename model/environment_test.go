@@ -0,0 +1,78 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeature_EnvironmentGate(t *testing.T) {
+	staging := &Gate{Groups: []string{"beta"}}
+	prod := &Gate{Value: "true", Seed: "prod"}
+	f := Feature{
+		Gate:         &Gate{Value: "false", Seed: "foo"},
+		Environments: map[string]*Gate{"staging": staging, "prod": prod},
+	}
+
+	assert.Equal(t, f.Gate, f.EnvironmentGate(""))
+	assert.Equal(t, f.Gate, f.EnvironmentGate("dev"))
+	assert.Equal(t, prod, f.EnvironmentGate("prod"))
+	assert.Equal(t, &Gate{Groups: []string{"beta"}, Seed: "foo"}, f.EnvironmentGate("staging"))
+	assert.Empty(t, staging.Seed)
+
+	scoped := f.InEnvironment("staging")
+	assert.Equal(t, []string{"beta"}, scoped.Gate.Groups)
+	assert.Nil(t, scoped.Environments)
+	assert.Len(t, f.Environments, 2)
+}
+
+func TestFeature_Promote(t *testing.T) {
+	f := Feature{
+		Gate:         &Gate{Value: "false"},
+		Environments: map[string]*Gate{"staging": {Groups: []string{"beta"}}},
+	}
+
+	assert.Error(t, f.Promote("staging", "staging"))
+	assert.Error(t, f.Promote("dev", "prod"))
+	assert.NoError(t, f.Promote("staging", "prod"))
+	assert.Equal(t, []string{"beta"}, f.Environments["prod"].Groups)
+
+	f.Environments["prod"].Groups = []string{"everyone"}
+	assert.Equal(t, []string{"beta"}, f.Environments["staging"].Groups)
+}
+
+var validateEnvironmentsTests = []struct {
+	environments map[string]*Gate
+	valid        bool
+}{
+	{nil, true},
+	{map[string]*Gate{"staging": {Value: "true"}}, true},
+	{map[string]*Gate{"": {Value: "true"}}, false},
+	{map[string]*Gate{"staging": nil}, false},
+	{map[string]*Gate{"staging": {Rules: []Rule{{Attribute: "country", Operator: "like"}}}}, false},
+}
+
+func TestFeature_Validate_Environments(t *testing.T) {
+	for i, tt := range validateEnvironmentsTests {
+		f := Feature{Type: BoolValueType, Value: "true", Gate: &Gate{}, Environments: tt.environments}
+		assert.Equal(t, tt.valid, f.Validate() == nil, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestFeature_SegmentNames(t *testing.T) {
+	f := Feature{
+		Gate:         &Gate{Segments: []string{"beta"}},
+		Environments: map[string]*Gate{"staging": {Segments: []string{"staff"}}},
+	}
+	assert.Equal(t, []string{"beta", "staff"}, f.SegmentNames())
+}
+
+func TestFeature_Diff_Environments(t *testing.T) {
+	f1 := Feature{Gate: &Gate{}, Environments: map[string]*Gate{"dev": {Value: "true"}, "staging": {Value: "false"}}}
+	f2 := Feature{Gate: &Gate{}, Environments: map[string]*Gate{"staging": {Value: "true"}}}
+
+	fields := f1.Diff(&f2)
+	assert.Equal(t, `{"value":"true"} -> NO_VALUE`, fields["environment_dev"])
+	assert.Equal(t, `{"value":"false"} -> {"value":"true"}`, fields["environment_staging"])
+}
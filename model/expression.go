@@ -0,0 +1,176 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expression combines gates with boolean operators. Exactly one of And, Or,
+// Not or Gate is set on every node of the expression.
+//
+// A Gate leaf is evaluated like a top-level gate, enabling if any of its types
+// do. Leaves without a seed use the seed of the gate holding the expression, so
+// their percentages bucket actors the same way. Deny lists and expressions are
+// only allowed on the top-level gate.
+type Expression struct {
+	And  []Expression `json:"and,omitempty"`
+	Or   []Expression `json:"or,omitempty"`
+	Not  *Expression  `json:"not,omitempty"`
+	Gate *Gate        `json:"gate,omitempty"`
+}
+
+// maxExpressionDepth limits how deeply expressions can be nested.
+const maxExpressionDepth = 10
+
+// Validate checks that the expression and all of its gates can be evaluated.
+func (e *Expression) Validate() error {
+	return e.validate(1)
+}
+
+func (e *Expression) validate(depth int) error {
+	if depth > maxExpressionDepth {
+		return fmt.Errorf("expression is nested deeper than %d levels", maxExpressionDepth)
+	}
+
+	operators := 0
+	if e.And != nil {
+		operators++
+	}
+	if e.Or != nil {
+		operators++
+	}
+	if e.Not != nil {
+		operators++
+	}
+	if e.Gate != nil {
+		operators++
+	}
+	if operators != 1 {
+		return fmt.Errorf("expression must have exactly one of and, or, not or gate")
+	}
+
+	switch {
+	case e.And != nil || e.Or != nil:
+		operands := e.operands()
+		if len(operands) == 0 {
+			return fmt.Errorf("expression operator has no operands")
+		}
+		for _, o := range operands {
+			if err := o.validate(depth + 1); err != nil {
+				return err
+			}
+		}
+	case e.Not != nil:
+		return e.Not.validate(depth + 1)
+	default:
+		if e.Gate.Expression != nil {
+			return fmt.Errorf("expression gates cannot have an expression, use and, or and not instead")
+		}
+		if len(e.Gate.DenyActors) > 0 || len(e.Gate.DenyGroups) > 0 {
+			return fmt.Errorf("expression gates cannot have deny lists")
+		}
+		if len(e.Gate.Types()) == 0 {
+			return fmt.Errorf("expression gate has no gate types")
+		}
+		return e.Gate.Validate()
+	}
+	return nil
+}
+
+func (e *Expression) operands() []Expression {
+	if e.And != nil {
+		return e.And
+	}
+	return e.Or
+}
+
+// Segments returns the names of all segments referred to by the expression's
+// gates.
+func (e *Expression) Segments() (names []string) {
+	for _, o := range e.operands() {
+		names = append(names, o.Segments()...)
+	}
+	if e.Not != nil {
+		names = append(names, e.Not.Segments()...)
+	}
+	if e.Gate != nil {
+		names = append(names, e.Gate.Segments...)
+	}
+	return
+}
+
+// String returns a human-readable representation of the expression, used in
+// audit diffs, e.g. "actors(one) OR (groups(staff) AND NOT rules(country equals CN))".
+func (e *Expression) String() string {
+	if e == nil {
+		return ""
+	}
+	return e.format(false)
+}
+
+func (e *Expression) format(nested bool) string {
+	var s string
+	switch {
+	case e.And != nil:
+		s = joinExpressions(e.And, " AND ")
+	case e.Or != nil:
+		s = joinExpressions(e.Or, " OR ")
+	case e.Not != nil:
+		return "NOT " + e.Not.format(true)
+	case e.Gate != nil:
+		s = describeGate(e.Gate)
+		if len(e.Gate.Types()) < 2 {
+			return s
+		}
+	}
+	if nested {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+func joinExpressions(operands []Expression, operator string) string {
+	s := make([]string, len(operands))
+	for i, o := range operands {
+		s[i] = o.format(len(operands) > 1)
+	}
+	return strings.Join(s, operator)
+}
+
+// describeGate renders each of the gate's types, joined by OR.
+func describeGate(g *Gate) string {
+	types := g.Types()
+	s := make([]string, len(types))
+	for i, t := range types {
+		var config string
+		switch t {
+		case BooleanGateType:
+			config = g.Value
+		case ActorsGateType:
+			config = strings.Join(g.Actors, ",")
+		case PercentOfActorsGateType:
+			config = strings.Join(g.Actors, ",") + " at " + strconv.Itoa(g.ActorPercent) + "%"
+		case GroupsGateType:
+			config = strings.Join(g.Groups, ",")
+		case SegmentsGateType:
+			config = strings.Join(g.Segments, ",")
+		case RulesGateType:
+			config = joinRules(g.Rules)
+		case ScheduleGateType:
+			config = g.Schedule.String()
+		case PercentOfEveryoneGateType:
+			config = strconv.Itoa(g.PercentOfEveryone) + "%"
+		case PercentOfTimeGateType:
+			config = strconv.Itoa(g.PercentOfTime) + "%"
+		case ExpressionGateType:
+			config = g.Expression.String()
+		case CIDRsGateType:
+			config = strings.Join(g.CIDRs, ",")
+		default:
+			config = string(g.Custom[t])
+		}
+		s[i] = t + "(" + config + ")"
+	}
+	return strings.Join(s, " OR ")
+}
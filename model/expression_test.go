@@ -0,0 +1,81 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var expressionValidateTests = []struct {
+	expression Expression
+	valid      bool
+}{
+	{Expression{Gate: &Gate{Groups: []string{"beta"}}}, true},
+	{Expression{And: []Expression{{Gate: &Gate{Groups: []string{"beta"}}}, {Gate: &Gate{PercentOfEveryone: 20}}}}, true},
+	{Expression{Not: &Expression{Gate: &Gate{Actors: []string{"one"}}}}, true},
+	{Expression{}, false},
+	{Expression{And: []Expression{}}, false},
+	{Expression{Gate: &Gate{}}, false},
+	{Expression{Gate: &Gate{Groups: []string{"beta"}}, Not: &Expression{Gate: &Gate{Groups: []string{"beta"}}}}, false},
+	{Expression{Gate: &Gate{Groups: []string{"beta"}, DenyActors: []string{"one"}}}, false},
+	{Expression{Gate: &Gate{Expression: &Expression{Gate: &Gate{Groups: []string{"beta"}}}}}, false},
+	{Expression{Or: []Expression{{Gate: &Gate{Rules: []Rule{{Attribute: "country", Operator: "like", Values: []string{"DE"}}}}}}}, false},
+}
+
+func TestExpression_Validate(t *testing.T) {
+	for i, tt := range expressionValidateTests {
+		assert.Equal(t, tt.valid, tt.expression.Validate() == nil, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestExpression_Validate_Depth(t *testing.T) {
+	e := &Expression{Gate: &Gate{Groups: []string{"beta"}}}
+	for i := 0; i < maxExpressionDepth; i++ {
+		e = &Expression{Not: e}
+	}
+	assert.Error(t, e.Validate())
+	assert.NoError(t, e.Not.Validate())
+}
+
+var expressionStringTests = []struct {
+	expression Expression
+	expected   string
+}{
+	{
+		Expression{Gate: &Gate{Groups: []string{"beta"}, Actors: []string{"one", "two"}}},
+		"groups(beta) OR actors(one,two)",
+	},
+	{
+		Expression{Or: []Expression{
+			{Gate: &Gate{Actors: []string{"one"}}},
+			{And: []Expression{
+				{Gate: &Gate{Groups: []string{"staff"}}},
+				{Not: &Expression{Gate: &Gate{Rules: []Rule{{Attribute: "country", Operator: EqualsOperator, Values: []string{"CN"}}}}}},
+			}},
+		}},
+		"actors(one) OR (groups(staff) AND NOT rules(country equals CN))",
+	},
+	{
+		Expression{Not: &Expression{Gate: &Gate{Groups: []string{"beta"}, Value: "true"}}},
+		"NOT (boolean(true) OR groups(beta))",
+	},
+}
+
+func TestExpression_String(t *testing.T) {
+	for i, tt := range expressionStringTests {
+		assert.Equal(t, tt.expected, tt.expression.String(), fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestGate_SegmentNames(t *testing.T) {
+	g := Gate{
+		Segments: []string{"beta"},
+		Expression: &Expression{Or: []Expression{
+			{Gate: &Gate{Segments: []string{"staff"}}},
+			{Not: &Expression{Gate: &Gate{Segments: []string{"churned"}}}},
+		}},
+	}
+	assert.Equal(t, []string{"beta", "staff", "churned"}, g.SegmentNames())
+	assert.Equal(t, []string{"beta"}, g.Segments)
+}
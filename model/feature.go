@@ -1,21 +1,103 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 )
 
-// Feature NODOC
+// Feature is a gated value. Enabled contexts are served Value, or the value of
+// their variant, and disabled contexts are served OffValue, if set.
+//
+// Environments hold separate gate settings for each environment the feature is
+// evaluated in. The feature's own Gate is used outside of those environments.
+//
+// Lifecycle records who owns the feature and when it should be removed.
+//
+// Revision is set by the store, which increments it on every change. A feature
+// can only be saved or deleted at the revision it was read at.
 type Feature struct {
-	Namespace   string    `json:"namespace,omitempty"`
-	Key         string    `json:"key" binding:"required"`
-	Type        string    `json:"type" binding:"required"`
-	Value       string    `json:"value" binding:"required"`
-	Gate        *Gate     `json:"gate" binding:"required"`
-	DateCreated time.Time `json:"dateCreated"`
-	LastUpdated time.Time `json:"lastUpdated"`
+	Namespace     string           `json:"namespace,omitempty"`
+	Key           string           `json:"key" binding:"required"`
+	Type          string           `json:"type" binding:"required"`
+	Value         string           `json:"value" binding:"required"`
+	OffValue      string           `json:"offValue,omitempty"`
+	Schema        json.RawMessage  `json:"schema,omitempty"`
+	Gate          *Gate            `json:"gate" binding:"required"`
+	Environments  map[string]*Gate `json:"environments,omitempty"`
+	Variants      []Variant        `json:"variants,omitempty"`
+	Prerequisites []Prerequisite   `json:"prerequisites,omitempty"`
+	Rollout       *Rollout         `json:"rollout,omitempty"`
+	Lifecycle     *Lifecycle       `json:"lifecycle,omitempty"`
+	Revision      int64            `json:"revision"`
+	DateCreated   time.Time        `json:"dateCreated"`
+	LastUpdated   time.Time        `json:"lastUpdated"`
+}
+
+// Validate checks that the feature's configuration can be evaluated.
+func (f *Feature) Validate() error {
+	if err := f.validateValues(); err != nil {
+		return err
+	}
+	if err := f.Gate.Validate(); err != nil {
+		return err
+	}
+	if err := validateEnvironments(f.Environments); err != nil {
+		return err
+	}
+	if err := validateVariants(f.Variants); err != nil {
+		return err
+	}
+	if err := validatePrerequisites(f.Prerequisites); err != nil {
+		return err
+	}
+	if f.Rollout != nil {
+		if err := f.Rollout.Validate(); err != nil {
+			return err
+		}
+	}
+	if f.Lifecycle != nil {
+		return f.Lifecycle.Validate()
+	}
+	return nil
+}
+
+// Copy returns a copy of the feature whose gate, rollout, lifecycle and
+// environments can be changed without affecting the original.
+func (f *Feature) Copy() *Feature {
+	c := *f
+	if f.Gate != nil {
+		gate := *f.Gate
+		c.Gate = &gate
+	}
+	if f.Rollout != nil {
+		rollout := *f.Rollout
+		c.Rollout = &rollout
+	}
+	if f.Lifecycle != nil {
+		lifecycle := *f.Lifecycle
+		c.Lifecycle = &lifecycle
+	}
+	if f.Environments != nil {
+		c.Environments = make(map[string]*Gate, len(f.Environments))
+		for env, g := range f.Environments {
+			c.Environments[env] = g
+		}
+	}
+	return &c
+}
+
+// ID returns the feature's key, prefixed by its namespace if it has one.
+func (f *Feature) ID() string {
+	return featureID(f.Namespace, f.Key)
+}
+
+// BucketSeed returns the default seed for percentage bucketing, which salts
+// actors with the feature's namespace and key.
+func (f *Feature) BucketSeed() string {
+	return f.ID()
 }
 
 // Diff returns a flatmap diff of two features, which can be used for auditing.
@@ -28,6 +110,32 @@ func (f *Feature) Diff(b *Feature) Fields {
 	if f.Value != b.Value {
 		d["value"] = f.diffValue(f.Value, b.Value)
 	}
+	if f.OffValue != b.OffValue {
+		d["off_value"] = f.diffValue(f.OffValue, b.OffValue)
+	}
+	if string(f.Schema) != string(b.Schema) {
+		d["schema"] = f.diffValue(string(f.Schema), string(b.Schema))
+	}
+	fVariants := joinVariants(f.Variants)
+	bVariants := joinVariants(b.Variants)
+	if fVariants != bVariants {
+		d["variants"] = f.diffValue(fVariants, bVariants)
+	}
+	fPrerequisites := joinPrerequisites(f.Prerequisites)
+	bPrerequisites := joinPrerequisites(b.Prerequisites)
+	if fPrerequisites != bPrerequisites {
+		d["prerequisites"] = f.diffValue(fPrerequisites, bPrerequisites)
+	}
+	fRollout := f.Rollout.String()
+	bRollout := b.Rollout.String()
+	if fRollout != bRollout {
+		d["rollout"] = f.diffValue(fRollout, bRollout)
+	}
+	fLifecycle := f.Lifecycle.String()
+	bLifecycle := b.Lifecycle.String()
+	if fLifecycle != bLifecycle {
+		d["lifecycle"] = f.diffValue(fLifecycle, bLifecycle)
+	}
 	if f.Gate.ActorPercent != b.Gate.ActorPercent {
 		d["gate_actor_percent"] = f.diffValue(strconv.Itoa(f.Gate.ActorPercent), strconv.Itoa(b.Gate.ActorPercent))
 	}
@@ -41,16 +149,76 @@ func (f *Feature) Diff(b *Feature) Fields {
 	if fGroups != bGroups {
 		d["gate_groups"] = f.diffValue(fGroups, bGroups)
 	}
+	fSegments := strings.Join(f.Gate.Segments, ",")
+	bSegments := strings.Join(b.Gate.Segments, ",")
+	if fSegments != bSegments {
+		d["gate_segments"] = f.diffValue(fSegments, bSegments)
+	}
+	fCIDRs := strings.Join(f.Gate.CIDRs, ",")
+	bCIDRs := strings.Join(b.Gate.CIDRs, ",")
+	if fCIDRs != bCIDRs {
+		d["gate_cidrs"] = f.diffValue(fCIDRs, bCIDRs)
+	}
+	fDenyActors := strings.Join(f.Gate.DenyActors, ",")
+	bDenyActors := strings.Join(b.Gate.DenyActors, ",")
+	if fDenyActors != bDenyActors {
+		d["gate_deny_actors"] = f.diffValue(fDenyActors, bDenyActors)
+	}
+	fDenyGroups := strings.Join(f.Gate.DenyGroups, ",")
+	bDenyGroups := strings.Join(b.Gate.DenyGroups, ",")
+	if fDenyGroups != bDenyGroups {
+		d["gate_deny_groups"] = f.diffValue(fDenyGroups, bDenyGroups)
+	}
+	if f.Gate.PercentOfEveryone != b.Gate.PercentOfEveryone {
+		d["gate_percent_of_everyone"] = f.diffValue(strconv.Itoa(f.Gate.PercentOfEveryone), strconv.Itoa(b.Gate.PercentOfEveryone))
+	}
 	if f.Gate.PercentOfTime != b.Gate.PercentOfTime {
 		d["gate_percent_of_time"] = f.diffValue(strconv.Itoa(f.Gate.PercentOfTime), strconv.Itoa(b.Gate.PercentOfTime))
 	}
 	if f.Gate.Value != b.Gate.Value {
 		d["gate_value"] = f.diffValue(f.Gate.Value, b.Gate.Value)
 	}
+	if f.Gate.Seed != b.Gate.Seed {
+		d["gate_seed"] = f.diffValue(f.Gate.Seed, b.Gate.Seed)
+	}
+	fSchedule := f.Gate.Schedule.String()
+	bSchedule := b.Gate.Schedule.String()
+	if fSchedule != bSchedule {
+		d["gate_schedule"] = f.diffValue(fSchedule, bSchedule)
+	}
+	fRules := joinRules(f.Gate.Rules)
+	bRules := joinRules(b.Gate.Rules)
+	if fRules != bRules {
+		d["gate_rules"] = f.diffValue(fRules, bRules)
+	}
+	fExpression := f.Gate.Expression.String()
+	bExpression := b.Gate.Expression.String()
+	if fExpression != bExpression {
+		d["gate_expression"] = f.diffValue(fExpression, bExpression)
+	}
+	for env := range f.Environments {
+		if _, ok := b.Environments[env]; !ok {
+			d["environment_"+env] = f.diffValue(gateString(f.Environments[env]), "")
+		}
+	}
+	for env, g := range b.Environments {
+		if fGate, bGate := gateString(f.Environments[env]), gateString(g); fGate != bGate {
+			d["environment_"+env] = f.diffValue(fGate, bGate)
+		}
+	}
+	fCustom := joinCustom(f.Gate.Custom)
+	bCustom := joinCustom(b.Gate.Custom)
+	if fCustom != bCustom {
+		d["gate_custom"] = f.diffValue(fCustom, bCustom)
+	}
 	return d
 }
 
 func (f *Feature) diffValue(from string, to string) string {
+	return diffValue(from, to)
+}
+
+func diffValue(from string, to string) string {
 	if from == "" {
 		from = "NO_VALUE"
 	}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,23 +20,81 @@ func TestFeature_Diff(t *testing.T) {
 		},
 	}
 	f2 := Feature{
-		Type:  "f2",
-		Value: "f2",
+		Type:      "f2",
+		Value:     "f2",
+		OffValue:  "off",
+		Lifecycle: &Lifecycle{Kind: PermanentLifecycle, Owner: "search"},
+		Variants: []Variant{
+			{Name: "control", Weight: 20, Value: "blue"},
+			{Name: "treatment", Weight: 80, Value: "green"},
+		},
 		Gate: &Gate{
-			Value:         "false",
-			Groups:        []string{"two"},
-			Actors:        []string{"one", "three"},
-			ActorPercent:  50,
-			PercentOfTime: 60,
+			Value:             "false",
+			Groups:            []string{"two"},
+			Actors:            []string{"one", "three"},
+			DenyActors:        []string{"broken"},
+			DenyGroups:        []string{"enterprise", "internal"},
+			ActorPercent:      50,
+			PercentOfTime:     60,
+			PercentOfEveryone: 5,
+			Rules: []Rule{
+				{Attribute: "country", Operator: InOperator, Values: []string{"DE", "FR"}},
+				{Attribute: "plan", Operator: EqualsOperator, Values: []string{"pro"}},
+			},
+			Schedule: &Schedule{
+				Windows: []Window{{Days: []string{"monday"}, Start: "09:00", End: "17:00"}},
+			},
+			Expression: &Expression{And: []Expression{
+				{Gate: &Gate{Groups: []string{"beta"}}},
+				{Gate: &Gate{PercentOfEveryone: 20}},
+			}},
 		},
 	}
 
 	fields := f1.Diff(&f2)
 	assert.Equal(t, "f1 -> f2", fields["type"], "type did not match")
 	assert.Equal(t, "f1 -> f2", fields["value"], "value did not match")
+	assert.Equal(t, "NO_VALUE -> off", fields["off_value"], "off_value did not match")
 	assert.Equal(t, "true -> false", fields["gate_value"], "gate_value did not match")
 	assert.Equal(t, "NO_VALUE -> two", fields["gate_groups"], "gate_groups did not match")
 	assert.Equal(t, "one -> one,three", fields["gate_actors"], "gate_actors did not match")
+	assert.Equal(t, "NO_VALUE -> broken", fields["gate_deny_actors"], "gate_deny_actors did not match")
+	assert.Equal(t, "NO_VALUE -> enterprise,internal", fields["gate_deny_groups"], "gate_deny_groups did not match")
 	assert.Equal(t, "10 -> 50", fields["gate_actor_percent"], "gate_actor_percent did not match")
 	assert.Equal(t, "10 -> 60", fields["gate_percent_of_time"], "gate_percent_of_time did not match")
+	assert.Equal(t, "0 -> 5", fields["gate_percent_of_everyone"], "gate_percent_of_everyone did not match")
+	assert.Equal(t, "NO_VALUE -> country in [DE,FR]; plan equals pro", fields["gate_rules"], "gate_rules did not match")
+	assert.Equal(t, "NO_VALUE -> monday 09:00-17:00 UTC", fields["gate_schedule"], "gate_schedule did not match")
+	assert.Equal(t, "NO_VALUE -> groups(beta) AND percentOfEveryone(20%)", fields["gate_expression"], "gate_expression did not match")
+	assert.NotContains(t, fields, "gate_seed")
+	assert.Equal(t, "NO_VALUE -> permanent owned by search", fields["lifecycle"], "lifecycle did not match")
+	assert.Equal(t, "NO_VALUE -> control:20:blue, treatment:80:green", fields["variants"], "variants did not match")
+}
+
+var featureValidateTests = []struct {
+	variants []Variant
+	valid    bool
+}{
+	{nil, true},
+	{[]Variant{{Name: "a", Weight: 1}, {Name: "b", Weight: 0}}, true},
+	{[]Variant{{Name: "a", Weight: 0}}, false},
+	{[]Variant{{Name: "a", Weight: 1}, {Name: "a", Weight: 1}}, false},
+	{[]Variant{{Name: "", Weight: 1}}, false},
+	{[]Variant{{Name: "a", Weight: 2}, {Name: "b", Weight: -1}}, false},
+}
+
+func TestFeature_Validate(t *testing.T) {
+	for i, tt := range featureValidateTests {
+		f := Feature{Type: StringValueType, Gate: &Gate{}, Variants: tt.variants}
+		if tt.valid {
+			assert.NoError(t, f.Validate(), fmt.Sprintf("case %d", i+1))
+		} else {
+			assert.Error(t, f.Validate(), fmt.Sprintf("case %d", i+1))
+		}
+	}
+}
+
+func TestFeature_BucketSeed(t *testing.T) {
+	assert.Equal(t, "foo", (&Feature{Key: "foo"}).BucketSeed())
+	assert.Equal(t, "mobile.ios/foo", (&Feature{Namespace: "mobile.ios", Key: "foo"}).BucketSeed())
 }
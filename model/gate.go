@@ -1,6 +1,9 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
+	"net"
 	"sort"
 
 	"github.com/Sirupsen/logrus"
@@ -15,25 +18,109 @@ import (
 // list of actors.
 //
 // PercentOfActorsGateType will first validate that the actor is allowed, then
-// hash the actor to see if they are within a percentage of enabled users in
-// that list.
+// hash the actor, salted with the gate's Seed, to see if they are within a
+// percentage of enabled users in that list.
+//
+// PercentOfEveryoneGateType will hash any actor, salted with the gate's Seed,
+// to see if they are within a percentage of all actors.
+//
+// SegmentsGateType will enable if the evaluation is in any of the named
+// segments.
+//
+// RulesGateType will enable if every rule matches the attributes given with
+// the evaluation.
+//
+// ScheduleGateType will enable during the gate's schedule.
 //
 // PercentOfTimeGateType will enable the gate a percentage of the time.
+//
+// CIDRsGateType will enable if the caller's IP is in any of the gate's CIDR
+// ranges.
+//
+// ExpressionGateType will enable if the gate's expression, combining other
+// gates with and, or and not, is true.
+//
+// Custom gate types can be added with RegisterGateType.
+//
+// DenyActors and DenyGroups are not gate types: they are checked before every
+// gate type and disable the gate for matching actors or groups.
 const (
-	BooleanGateType         = "boolean"
-	ActorsGateType          = "actors"
-	GroupsGateType          = "groups"
-	PercentOfActorsGateType = "percentOfActors"
-	PercentOfTimeGateType   = "percentOfTime"
+	BooleanGateType           = "boolean"
+	ActorsGateType            = "actors"
+	GroupsGateType            = "groups"
+	SegmentsGateType          = "segments"
+	RulesGateType             = "rules"
+	ScheduleGateType          = "schedule"
+	PercentOfActorsGateType   = "percentOfActors"
+	PercentOfEveryoneGateType = "percentOfEveryone"
+	PercentOfTimeGateType     = "percentOfTime"
+	ExpressionGateType        = "expression"
+	CIDRsGateType             = "cidrs"
 )
 
 // Gate NODOC
+//
+// The CIDR ranges are parsed once, when the gate is decoded or they are set
+// with SetCIDRs, so that they are not parsed again on every evaluation.
 type Gate struct {
-	Value         string   `json:"value,omitempty"`
-	Groups        []string `json:"groups,omitempty"`
-	Actors        []string `json:"actors,omitempty"`
-	ActorPercent  int      `json:"actorPercent,omitempty"`
-	PercentOfTime int      `json:"percentOfTime,omitempty"`
+	Value             string      `json:"value,omitempty"`
+	Groups            []string    `json:"groups,omitempty"`
+	Actors            []string    `json:"actors,omitempty"`
+	DenyActors        []string    `json:"denyActors,omitempty"`
+	DenyGroups        []string    `json:"denyGroups,omitempty"`
+	ActorPercent      int         `json:"actorPercent,omitempty"`
+	PercentOfEveryone int         `json:"percentOfEveryone,omitempty"`
+	PercentOfTime     int         `json:"percentOfTime,omitempty"`
+	Segments          []string    `json:"segments,omitempty"`
+	CIDRs             []string    `json:"cidrs,omitempty"`
+	Rules             []Rule      `json:"rules,omitempty"`
+	Schedule          *Schedule   `json:"schedule,omitempty"`
+	Expression        *Expression `json:"expression,omitempty"`
+	Seed              string      `json:"seed,omitempty"`
+
+	Custom map[string]json.RawMessage `json:"custom,omitempty"`
+
+	networks []*net.IPNet
+}
+
+// UnmarshalJSON decodes the gate and parses its CIDR ranges. Invalid ranges are
+// left for Validate to report.
+func (g *Gate) UnmarshalJSON(data []byte) error {
+	type gate Gate
+	var v gate
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*g = Gate(v)
+	g.SetCIDRs(g.CIDRs)
+	return nil
+}
+
+// SetCIDRs sets the gate's CIDR ranges and parses them.
+func (g *Gate) SetCIDRs(cidrs []string) {
+	g.CIDRs = cidrs
+	g.networks = parseCIDRs(cidrs)
+}
+
+// Networks returns the parsed CIDR ranges, in the order of CIDRs. Invalid
+// ranges are nil. Gates whose ranges were not decoded or set with SetCIDRs
+// parse them on every call.
+func (g *Gate) Networks() []*net.IPNet {
+	if len(g.networks) == len(g.CIDRs) {
+		return g.networks
+	}
+	return parseCIDRs(g.CIDRs)
+}
+
+func parseCIDRs(cidrs []string) []*net.IPNet {
+	if len(cidrs) == 0 {
+		return nil
+	}
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, networks[i], _ = net.ParseCIDR(cidr)
+	}
+	return networks
 }
 
 // Types returns a slice of all types of the gate, in order of evaluation
@@ -52,20 +139,68 @@ func (g *Gate) Types() (types []string) {
 	if len(g.Groups) > 0 {
 		types = append(types, GroupsGateType)
 	}
+	if len(g.Segments) > 0 {
+		types = append(types, SegmentsGateType)
+	}
+	if len(g.Rules) > 0 {
+		types = append(types, RulesGateType)
+	}
+	if g.Schedule != nil {
+		types = append(types, ScheduleGateType)
+	}
+	if g.PercentOfEveryone > 0 {
+		types = append(types, PercentOfEveryoneGateType)
+	}
 	if g.PercentOfTime > 0 {
 		types = append(types, PercentOfTimeGateType)
 	}
-	sort.Sort(byPrecedence(types))
+	if g.Expression != nil {
+		types = append(types, ExpressionGateType)
+	}
+	if len(g.CIDRs) > 0 {
+		types = append(types, CIDRsGateType)
+	}
+	for _, t := range customGateTypes() {
+		if t.Configured(g) {
+			types = append(types, t.Name)
+		}
+	}
+	sort.Stable(byPrecedence(types))
 	return
 }
 
-// From highest precedence to lowest.
-var gatePrecedence = []string{
-	BooleanGateType,
-	GroupsGateType,
-	ActorsGateType,
-	PercentOfActorsGateType,
-	PercentOfTimeGateType,
+// Validate checks that the gate's configuration can be evaluated.
+func (g *Gate) Validate() error {
+	for _, cidr := range g.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid CIDR range: %s", cidr)
+		}
+	}
+	for _, r := range g.Rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	if g.Schedule != nil {
+		if err := g.Schedule.Validate(); err != nil {
+			return err
+		}
+	}
+	if g.Expression != nil {
+		if err := g.Expression.Validate(); err != nil {
+			return err
+		}
+	}
+	return g.validateCustom()
+}
+
+// SegmentNames returns the names of all segments the gate refers to, including
+// those in its expression.
+func (g *Gate) SegmentNames() []string {
+	if g.Expression == nil {
+		return g.Segments
+	}
+	return append(g.Segments[:len(g.Segments):len(g.Segments)], g.Expression.Segments()...)
 }
 
 type byPrecedence []string
@@ -83,11 +218,9 @@ func (s byPrecedence) Less(i, j int) bool {
 }
 
 func (s byPrecedence) pos(val string) int {
-	for i, g := range gatePrecedence {
-		if g == val {
-			return i
-		}
+	p, ok := GatePrecedence(val)
+	if !ok {
+		logrus.WithField("val", val).Error("Unknown value for byPrecedence sorter")
 	}
-	logrus.WithField("val", val).Error("Unknown value for byPrecedence sorter")
-	return 0
+	return p
 }
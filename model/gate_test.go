@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/json"
+	"net"
 	"sort"
 	"testing"
 
@@ -19,6 +21,22 @@ var gateTypesTests = []struct {
 		Gate{Value: "invalid", Groups: []string{"one"}, Actors: []string{"one"}},
 		[]string{GroupsGateType, ActorsGateType},
 	},
+	{
+		Gate{PercentOfTime: 1, PercentOfEveryone: 5, Actors: []string{"one"}, ActorPercent: 10},
+		[]string{PercentOfActorsGateType, PercentOfEveryoneGateType, PercentOfTimeGateType},
+	},
+	{
+		Gate{PercentOfTime: 1, Rules: []Rule{{Attribute: "country", Operator: EqualsOperator, Values: []string{"DE"}}}},
+		[]string{RulesGateType, PercentOfTimeGateType},
+	},
+	{
+		Gate{PercentOfEveryone: 5, Schedule: &Schedule{}},
+		[]string{ScheduleGateType, PercentOfEveryoneGateType},
+	},
+	{
+		Gate{CIDRs: []string{"10.0.0.0/8"}, Groups: []string{"staff"}},
+		[]string{GroupsGateType, CIDRsGateType},
+	},
 }
 
 func TestGate_Types(t *testing.T) {
@@ -34,6 +52,30 @@ var byPrecedenceTests = []struct {
 	{[]string{BooleanGateType, GroupsGateType}, []string{BooleanGateType, GroupsGateType}},
 	{[]string{GroupsGateType, BooleanGateType}, []string{BooleanGateType, GroupsGateType}},
 	{[]string{PercentOfTimeGateType, PercentOfActorsGateType}, []string{PercentOfActorsGateType, PercentOfTimeGateType}},
+	{[]string{PercentOfTimeGateType, PercentOfEveryoneGateType}, []string{PercentOfEveryoneGateType, PercentOfTimeGateType}},
+	{[]string{PercentOfActorsGateType, ScheduleGateType, RulesGateType}, []string{RulesGateType, ScheduleGateType, PercentOfActorsGateType}},
+}
+
+func TestGate_Validate_CIDRs(t *testing.T) {
+	assert.NoError(t, (&Gate{CIDRs: []string{"10.0.0.0/8", "2001:db8::/32"}}).Validate())
+	assert.Error(t, (&Gate{CIDRs: []string{"10.0.0.1"}}).Validate())
+	assert.Error(t, (&Gate{CIDRs: []string{"10.0.0.0/33"}}).Validate())
+}
+
+func TestGate_UnmarshalJSON(t *testing.T) {
+	var g Gate
+	assert.NoError(t, json.Unmarshal([]byte(`{"cidrs": ["10.0.0.0/8", "10.0.0.1"]}`), &g))
+
+	networks := g.Networks()
+	if assert.Len(t, networks, 2) {
+		assert.True(t, networks[0].Contains(net.ParseIP("10.1.2.3")))
+		assert.Nil(t, networks[1])
+		assert.True(t, networks[0] == g.networks[0], "ranges were parsed again")
+	}
+	assert.Error(t, g.Validate())
+
+	g.SetCIDRs([]string{"192.168.0.0/16"})
+	assert.True(t, g.Networks()[0].Contains(net.ParseIP("192.168.0.1")))
 }
 
 func TestByPrecedenceSorter(t *testing.T) {
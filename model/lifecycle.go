@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TemporaryLifecycle features, such as release toggles and experiments, are
+// expected to be removed once they are no longer needed.
+//
+// PermanentLifecycle features, such as kill switches and entitlements, are
+// kept indefinitely and are never reported as stale.
+const (
+	TemporaryLifecycle = "temporary"
+	PermanentLifecycle = "permanent"
+)
+
+// Lifecycle describes who owns a feature and how long it is expected to live.
+type Lifecycle struct {
+	Kind     string     `json:"kind"`
+	Owner    string     `json:"owner,omitempty"`
+	RemoveBy *time.Time `json:"removeBy,omitempty"`
+}
+
+// Validate checks the lifecycle's kind. Only temporary features can have a
+// removal date.
+func (l *Lifecycle) Validate() error {
+	switch l.Kind {
+	case TemporaryLifecycle:
+	case PermanentLifecycle:
+		if l.RemoveBy != nil {
+			return fmt.Errorf("%s features cannot have a removal date", PermanentLifecycle)
+		}
+	default:
+		return fmt.Errorf("unknown lifecycle kind: %s", l.Kind)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the lifecycle, used in
+// audit diffs, e.g. "temporary owned by search until 2017-06-01".
+func (l *Lifecycle) String() string {
+	if l == nil {
+		return ""
+	}
+	s := []string{l.Kind}
+	if l.Owner != "" {
+		s = append(s, "owned by "+l.Owner)
+	}
+	if l.RemoveBy != nil {
+		s = append(s, "until "+l.RemoveBy.UTC().Format("2006-01-02"))
+	}
+	return strings.Join(s, " ")
+}
+
+// Overdue returns whether the feature is temporary and past its removal date.
+func (f *Feature) Overdue(now time.Time) bool {
+	l := f.Lifecycle
+	return l != nil && l.Kind == TemporaryLifecycle && l.RemoveBy != nil && now.After(*l.RemoveBy)
+}
+
+// RolledOutSince returns when the feature's gate was last turned on for
+// everyone, and whether it is still on for everyone. Permanent features are
+// never rolled out.
+//
+// The gate is assumed to have changed when the feature was last updated,
+// unless the breadcrumbs show that the feature was created or its gate value
+// was changed earlier. Audit breadcrumbs that do not carry the feature's key
+// and namespace are ignored.
+func (f *Feature) RolledOutSince(breadcrumbs []*Breadcrumb) (time.Time, bool) {
+	if f.Lifecycle != nil && f.Lifecycle.Kind == PermanentLifecycle {
+		return time.Time{}, false
+	}
+	g := f.Gate
+	if g == nil || g.Value != "true" || len(g.DenyActors) > 0 || len(g.DenyGroups) > 0 {
+		return time.Time{}, false
+	}
+
+	var changed time.Time
+	for _, b := range breadcrumbs {
+		if b.Fields["key"] != f.Key || b.Fields["ns"] != f.Namespace {
+			continue
+		}
+		if _, ok := b.Fields["gate_value"]; !ok && b.Action != CreateFeatureAction {
+			continue
+		}
+		// Breadcrumbs from before the feature was created belong to a feature
+		// of the same name that was deleted.
+		if b.DateCreated.Before(f.DateCreated) {
+			continue
+		}
+		if b.DateCreated.After(changed) {
+			changed = b.DateCreated
+		}
+	}
+	if changed.IsZero() || changed.After(f.LastUpdated) {
+		return f.LastUpdated, true
+	}
+	return changed, true
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func timeRef(t time.Time) *time.Time {
+	return &t
+}
+
+var lifecycleValidateTests = []struct {
+	lifecycle *Lifecycle
+	valid     bool
+}{
+	{&Lifecycle{Kind: TemporaryLifecycle}, true},
+	{&Lifecycle{Kind: TemporaryLifecycle, Owner: "search", RemoveBy: timeRef(time.Now())}, true},
+	{&Lifecycle{Kind: PermanentLifecycle, Owner: "search"}, true},
+	{&Lifecycle{Kind: PermanentLifecycle, RemoveBy: timeRef(time.Now())}, false},
+	{&Lifecycle{Kind: "forever"}, false},
+	{&Lifecycle{}, false},
+}
+
+func TestLifecycle_Validate(t *testing.T) {
+	for i, tt := range lifecycleValidateTests {
+		err := tt.lifecycle.Validate()
+		assert.Equal(t, tt.valid, err == nil, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestLifecycle_String(t *testing.T) {
+	l := &Lifecycle{Kind: TemporaryLifecycle, Owner: "search", RemoveBy: timeRef(time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC))}
+	assert.Equal(t, "temporary owned by search until 2017-06-01", l.String())
+	assert.Equal(t, "", (*Lifecycle)(nil).String())
+}
+
+func TestFeature_Overdue(t *testing.T) {
+	now := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	f := Feature{}
+	assert.False(t, f.Overdue(now))
+
+	f.Lifecycle = &Lifecycle{Kind: TemporaryLifecycle}
+	assert.False(t, f.Overdue(now))
+
+	f.Lifecycle.RemoveBy = timeRef(now.AddDate(0, 0, 1))
+	assert.False(t, f.Overdue(now))
+
+	f.Lifecycle.RemoveBy = timeRef(now.AddDate(0, 0, -1))
+	assert.True(t, f.Overdue(now))
+
+	f.Lifecycle.Kind = PermanentLifecycle
+	assert.False(t, f.Overdue(now))
+}
+
+func TestFeature_RolledOutSince(t *testing.T) {
+	created := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	enabled := created.AddDate(0, 1, 0)
+	updated := created.AddDate(0, 2, 0)
+	f := Feature{
+		Key:         "foo",
+		Gate:        &Gate{Value: "true"},
+		DateCreated: created,
+		LastUpdated: updated,
+	}
+	breadcrumb := func(action string, date time.Time, fields Fields) *Breadcrumb {
+		return &Breadcrumb{Action: action, DateCreated: date, Fields: fields}
+	}
+
+	since, ok := f.RolledOutSince(nil)
+	assert.True(t, ok)
+	assert.Equal(t, updated, since, "no history uses the last update")
+
+	breadcrumbs := []*Breadcrumb{
+		breadcrumb(CreateFeatureAction, created.AddDate(-1, 0, 0), Fields{"key": "foo", "ns": ""}),
+		breadcrumb(UpdateFeatureAction, enabled, Fields{"key": "foo", "ns": "", "gate_value": "NO_VALUE -> true"}),
+		breadcrumb(UpdateFeatureAction, enabled.AddDate(0, 0, 1), Fields{"key": "foo", "ns": "", "value": "a -> b"}),
+		breadcrumb(UpdateFeatureAction, enabled.AddDate(0, 0, 2), Fields{"key": "foo", "ns": "api", "gate_value": "true -> false"}),
+		breadcrumb(UpdateFeatureAction, enabled.AddDate(0, 0, 3), Fields{"gate_value": "true -> false"}),
+	}
+	since, ok = f.RolledOutSince(breadcrumbs)
+	assert.True(t, ok)
+	assert.Equal(t, enabled, since)
+
+	since, ok = f.RolledOutSince(breadcrumbs[:1])
+	assert.True(t, ok)
+	assert.Equal(t, updated, since, "breadcrumbs of a deleted feature are ignored")
+
+	since, ok = f.RolledOutSince([]*Breadcrumb{breadcrumb(CreateFeatureAction, created, Fields{"key": "foo", "ns": ""})})
+	assert.True(t, ok)
+	assert.Equal(t, created, since)
+
+	f.Gate.DenyGroups = []string{"enterprise"}
+	_, ok = f.RolledOutSince(breadcrumbs)
+	assert.False(t, ok)
+
+	f.Gate = &Gate{Value: "true"}
+	f.Lifecycle = &Lifecycle{Kind: PermanentLifecycle}
+	_, ok = f.RolledOutSince(breadcrumbs)
+	assert.False(t, ok)
+}
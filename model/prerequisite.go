@@ -0,0 +1,118 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Prerequisite requires another feature, possibly in another namespace, to
+// evaluate to the Enabled state for the same context before a feature can be
+// enabled.
+//
+// Enabled is true unless it is set to false: a prerequisite decoded without it
+// requires the other feature to be enabled, and only one with "enabled": false
+// requires it to be disabled.
+type Prerequisite struct {
+	Namespace string `json:"namespace,omitempty"`
+	Key       string `json:"key"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// UnmarshalJSON decodes the prerequisite, defaulting Enabled to true.
+func (p *Prerequisite) UnmarshalJSON(data []byte) error {
+	type prerequisite Prerequisite
+	in := prerequisite{Enabled: true}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*p = Prerequisite(in)
+	return nil
+}
+
+// FeatureLookup returns a feature by namespace and key, or nil if it does not
+// exist.
+type FeatureLookup func(namespace string, key string) (*Feature, error)
+
+// ID returns the namespaced key of the required feature.
+func (p Prerequisite) ID() string {
+	return featureID(p.Namespace, p.Key)
+}
+
+// String returns a human-readable representation of the prerequisite, used in
+// audit diffs.
+func (p Prerequisite) String() string {
+	return fmt.Sprintf("%s=%t", p.ID(), p.Enabled)
+}
+
+// DependsOn returns whether the feature has a prerequisite on the given
+// feature.
+func (f *Feature) DependsOn(namespace string, key string) bool {
+	for _, p := range f.Prerequisites {
+		if p.Namespace == namespace && p.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// PrerequisiteCycle follows the feature's prerequisites transitively and
+// returns the path of feature IDs leading back to the feature, or nil if there
+// is no cycle. The feature itself is never looked up, so that unsaved
+// prerequisites are checked.
+func (f *Feature) PrerequisiteCycle(lookup FeatureLookup) ([]string, error) {
+	root := f.ID()
+	visited := make(map[string]bool)
+
+	var walk func(prerequisites []Prerequisite, path []string) ([]string, error)
+	walk = func(prerequisites []Prerequisite, path []string) ([]string, error) {
+		for _, p := range prerequisites {
+			id := p.ID()
+			if id == root {
+				return append(path, id), nil
+			}
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+
+			dependency, err := lookup(p.Namespace, p.Key)
+			if err != nil {
+				return nil, err
+			}
+			if dependency == nil {
+				continue
+			}
+			cycle, err := walk(dependency.Prerequisites, append(path[:len(path):len(path)], id))
+			if err != nil || cycle != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+	return walk(f.Prerequisites, []string{root})
+}
+
+func validatePrerequisites(prerequisites []Prerequisite) error {
+	for _, p := range prerequisites {
+		if p.Key == "" {
+			return fmt.Errorf("prerequisite is missing a key")
+		}
+	}
+	return nil
+}
+
+func joinPrerequisites(prerequisites []Prerequisite) string {
+	s := make([]string, len(prerequisites))
+	for i, p := range prerequisites {
+		s[i] = p.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+func featureID(namespace string, key string) string {
+	if namespace == "" {
+		return key
+	}
+	return namespace + "/" + key
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lookupFrom(features ...*Feature) FeatureLookup {
+	return func(namespace string, key string) (*Feature, error) {
+		for _, f := range features {
+			if f.Namespace == namespace && f.Key == key {
+				return f, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+func TestFeature_PrerequisiteCycle(t *testing.T) {
+	a := &Feature{Key: "a", Prerequisites: []Prerequisite{{Namespace: "ns", Key: "b", Enabled: true}}}
+	b := &Feature{Namespace: "ns", Key: "b", Prerequisites: []Prerequisite{{Key: "c", Enabled: true}}}
+	c := &Feature{Key: "c"}
+	cToA := &Feature{Key: "c", Prerequisites: []Prerequisite{{Key: "a", Enabled: false}}}
+
+	tests := []struct {
+		feature  *Feature
+		lookup   FeatureLookup
+		expected []string
+	}{
+		{a, lookupFrom(b, c), nil},
+		{a, lookupFrom(b, cToA), []string{"a", "ns/b", "c", "a"}},
+		{&Feature{Key: "a", Prerequisites: []Prerequisite{{Key: "a"}}}, lookupFrom(), []string{"a", "a"}},
+		{&Feature{Key: "a", Prerequisites: []Prerequisite{{Key: "missing"}}}, lookupFrom(), nil},
+		// Diamonds are not cycles.
+		{
+			&Feature{Key: "a", Prerequisites: []Prerequisite{{Namespace: "ns", Key: "b"}, {Key: "c"}}},
+			lookupFrom(b, c),
+			nil,
+		},
+	}
+	for i, tt := range tests {
+		cycle, err := tt.feature.PrerequisiteCycle(tt.lookup)
+		assert.NoError(t, err, fmt.Sprintf("case %d", i+1))
+		assert.Equal(t, tt.expected, cycle, fmt.Sprintf("case %d", i+1))
+	}
+
+	_, err := a.PrerequisiteCycle(func(string, string) (*Feature, error) {
+		return nil, errors.New("oh no")
+	})
+	assert.Error(t, err)
+}
+
+func TestFeature_DependsOn(t *testing.T) {
+	f := &Feature{Key: "a", Prerequisites: []Prerequisite{{Namespace: "ns", Key: "b", Enabled: true}}}
+	assert.True(t, f.DependsOn("ns", "b"))
+	assert.False(t, f.DependsOn("", "b"))
+}
+
+func TestPrerequisite_UnmarshalJSON(t *testing.T) {
+	cases := []struct {
+		data     string
+		expected Prerequisite
+	}{
+		{`{"namespace":"api","key":"backend"}`, Prerequisite{Namespace: "api", Key: "backend", Enabled: true}},
+		{`{"key":"backend","enabled":true}`, Prerequisite{Key: "backend", Enabled: true}},
+		{`{"key":"backend","enabled":false}`, Prerequisite{Key: "backend", Enabled: false}},
+	}
+	for i, c := range cases {
+		var p Prerequisite
+		assert.NoError(t, json.Unmarshal([]byte(c.data), &p))
+		assert.Equal(t, c.expected, p, fmt.Sprintf("case %d", i+1))
+	}
+}
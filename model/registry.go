@@ -0,0 +1,132 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// GateType describes a custom gate type, registered in addition to the
+// built-in ones.
+//
+// Precedence orders the type among all others, lowest first. Built-in types
+// are spaced 100 apart, from 100 for BooleanGateType to 1100 for
+// CIDRsGateType, so a type can be placed between any two of them.
+// Types with the same precedence keep the order they were registered in.
+//
+// Configured returns whether the type applies to a gate. It defaults to
+// whether the gate has custom configuration under the type's Name.
+//
+// Validate optionally checks the type's configuration when a feature is
+// saved.
+//
+// Custom configuration is kept in the gate's Custom map under the type's Name
+// and is persisted by every store along with the rest of the gate.
+type GateType struct {
+	Name       string
+	Precedence int
+	Configured func(g *Gate) bool
+	Validate   func(g *Gate) error
+}
+
+var gateTypes = struct {
+	sync.RWMutex
+	precedence map[string]int
+	custom     []GateType
+}{
+	precedence: map[string]int{
+		BooleanGateType:           100,
+		GroupsGateType:            200,
+		ActorsGateType:            300,
+		SegmentsGateType:          400,
+		RulesGateType:             500,
+		ScheduleGateType:          600,
+		PercentOfActorsGateType:   700,
+		PercentOfEveryoneGateType: 800,
+		PercentOfTimeGateType:     900,
+		ExpressionGateType:        1000,
+		CIDRsGateType:             1100,
+	},
+}
+
+// RegisterGateType adds a custom gate type. It is meant to be called once per
+// type during initialization, and fails if the name is already taken.
+func RegisterGateType(t GateType) error {
+	if t.Name == "" {
+		return fmt.Errorf("gate type is missing a name")
+	}
+	if t.Configured == nil {
+		name := t.Name
+		t.Configured = func(g *Gate) bool {
+			_, ok := g.Custom[name]
+			return ok
+		}
+	}
+
+	gateTypes.Lock()
+	defer gateTypes.Unlock()
+	if _, ok := gateTypes.precedence[t.Name]; ok {
+		return fmt.Errorf("gate type is already registered: %s", t.Name)
+	}
+	gateTypes.precedence[t.Name] = t.Precedence
+	gateTypes.custom = append(gateTypes.custom, t)
+	return nil
+}
+
+// GatePrecedence returns the precedence of a built-in or registered gate type,
+// and whether the type is known.
+func GatePrecedence(gateType string) (int, bool) {
+	gateTypes.RLock()
+	defer gateTypes.RUnlock()
+	p, ok := gateTypes.precedence[gateType]
+	return p, ok
+}
+
+func customGateTypes() []GateType {
+	gateTypes.RLock()
+	defer gateTypes.RUnlock()
+	return gateTypes.custom
+}
+
+// CustomConfig decodes the custom configuration of a gate type into v. It
+// leaves v as is if the gate has no configuration for the type.
+func (g *Gate) CustomConfig(gateType string, v interface{}) error {
+	raw, ok := g.Custom[gateType]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid %s gate configuration: %s", gateType, err)
+	}
+	return nil
+}
+
+func (g *Gate) validateCustom() error {
+	types := customGateTypes()
+	known := make(map[string]bool, len(types))
+	for _, t := range types {
+		known[t.Name] = true
+		if t.Validate != nil && t.Configured(g) {
+			if err := t.Validate(g); err != nil {
+				return err
+			}
+		}
+	}
+	for name := range g.Custom {
+		if !known[name] {
+			return fmt.Errorf("unknown custom gate type: %s", name)
+		}
+	}
+	return nil
+}
+
+func joinCustom(custom map[string]json.RawMessage) string {
+	s := make([]string, 0, len(custom))
+	for name, raw := range custom {
+		s = append(s, name+"="+string(raw))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type regionConfig struct {
+	Regions []string `json:"regions"`
+}
+
+func init() {
+	err := RegisterGateType(GateType{
+		Name:       "testRegion",
+		Precedence: 550,
+		Validate: func(g *Gate) error {
+			var c regionConfig
+			if err := g.CustomConfig("testRegion", &c); err != nil {
+				return err
+			}
+			if len(c.Regions) == 0 {
+				return errors.New("region gate has no regions")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestRegisterGateType(t *testing.T) {
+	assert.Error(t, RegisterGateType(GateType{}))
+	assert.Error(t, RegisterGateType(GateType{Name: BooleanGateType}))
+	assert.Error(t, RegisterGateType(GateType{Name: "testRegion"}))
+
+	p, ok := GatePrecedence("testRegion")
+	assert.True(t, ok)
+	assert.Equal(t, 550, p)
+	_, ok = GatePrecedence("unknown")
+	assert.False(t, ok)
+}
+
+func TestGate_Types_Custom(t *testing.T) {
+	g := Gate{
+		PercentOfTime: 1,
+		Rules:         []Rule{{Attribute: "country", Operator: EqualsOperator, Values: []string{"DE"}}},
+		Custom:        map[string]json.RawMessage{"testRegion": json.RawMessage(`{"regions":["eu"]}`)},
+	}
+	assert.Equal(t, []string{RulesGateType, "testRegion", PercentOfTimeGateType}, g.Types())
+}
+
+var validateCustomTests = []struct {
+	custom map[string]json.RawMessage
+	valid  bool
+}{
+	{nil, true},
+	{map[string]json.RawMessage{"testRegion": json.RawMessage(`{"regions":["eu"]}`)}, true},
+	{map[string]json.RawMessage{"testRegion": json.RawMessage(`{"regions":[]}`)}, false},
+	{map[string]json.RawMessage{"testRegion": json.RawMessage(`{"regions":"eu"}`)}, false},
+	{map[string]json.RawMessage{"unknown": json.RawMessage(`{}`)}, false},
+}
+
+func TestGate_Validate_Custom(t *testing.T) {
+	for i, tt := range validateCustomTests {
+		g := Gate{Custom: tt.custom}
+		assert.Equal(t, tt.valid, g.Validate() == nil, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestGate_CustomConfig(t *testing.T) {
+	g := Gate{Custom: map[string]json.RawMessage{"testRegion": json.RawMessage(`{"regions":["eu"]}`)}}
+
+	var c regionConfig
+	assert.NoError(t, g.CustomConfig("testRegion", &c))
+	assert.Equal(t, []string{"eu"}, c.Regions)
+
+	var missing regionConfig
+	assert.NoError(t, g.CustomConfig("other", &missing))
+	assert.Empty(t, missing.Regions)
+}
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RolloutRunningState advances to the next step once NextStepAt has passed.
+//
+// RolloutPausedState holds the current step until the rollout is resumed.
+//
+// RolloutAbortedState stops the rollout and disables the gate's actors.
+//
+// RolloutCompletedState has reached the last step.
+const (
+	RolloutRunningState   = "running"
+	RolloutPausedState    = "paused"
+	RolloutAbortedState   = "aborted"
+	RolloutCompletedState = "completed"
+)
+
+// Rollout is a plan that ramps a gate's ActorPercent through each of its steps,
+// advancing one step every interval. The interval is a duration such as "24h".
+type Rollout struct {
+	Steps      []int      `json:"steps"`
+	Interval   string     `json:"interval"`
+	State      string     `json:"state,omitempty"`
+	Step       int        `json:"step"`
+	NextStepAt *time.Time `json:"nextStepAt,omitempty"`
+}
+
+// Validate checks that the rollout plan can be advanced.
+func (r *Rollout) Validate() error {
+	if len(r.Steps) == 0 {
+		return fmt.Errorf("rollout has no steps")
+	}
+	for i, p := range r.Steps {
+		if p < 1 || p > 100 {
+			return fmt.Errorf("rollout step must be between 1 and 100: %d", p)
+		}
+		if i > 0 && p <= r.Steps[i-1] {
+			return fmt.Errorf("rollout steps must be increasing")
+		}
+	}
+	if _, err := r.Duration(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Duration returns the time between two steps.
+func (r *Rollout) Duration() (time.Duration, error) {
+	d, err := time.ParseDuration(r.Interval)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid rollout interval: %s", r.Interval)
+	}
+	return d, nil
+}
+
+// SamePlan returns whether both rollouts have the same steps and interval,
+// regardless of their progress.
+func (r *Rollout) SamePlan(b *Rollout) bool {
+	return b != nil && r.Interval == b.Interval && joinSteps(r.Steps) == joinSteps(b.Steps)
+}
+
+// Active returns whether the rollout is in control of the gate's actor percent.
+func (r *Rollout) Active() bool {
+	return r.State == RolloutRunningState || r.State == RolloutPausedState
+}
+
+// Percent returns the actor percent of the current step.
+func (r *Rollout) Percent() int {
+	if r.State == RolloutAbortedState || r.Step < 0 || r.Step >= len(r.Steps) {
+		return 0
+	}
+	return r.Steps[r.Step]
+}
+
+// Due returns whether the rollout should advance to its next step.
+func (r *Rollout) Due(now time.Time) bool {
+	return r.State == RolloutRunningState && r.NextStepAt != nil && !now.Before(*r.NextStepAt)
+}
+
+// Start begins the rollout at its first step.
+func (r *Rollout) Start(now time.Time) {
+	r.State = RolloutRunningState
+	r.Step = 0
+	r.scheduleNext(now)
+}
+
+// Advance moves the rollout to its next step, completing it after the last.
+func (r *Rollout) Advance(now time.Time) {
+	if r.Step < len(r.Steps)-1 {
+		r.Step++
+	}
+	r.scheduleNext(now)
+}
+
+// Pause holds a running rollout at its current step.
+func (r *Rollout) Pause() error {
+	if r.State != RolloutRunningState {
+		return fmt.Errorf("cannot pause a %s rollout", r.State)
+	}
+	r.State = RolloutPausedState
+	r.NextStepAt = nil
+	return nil
+}
+
+// Resume continues a paused rollout. The current step is held for another
+// full interval.
+func (r *Rollout) Resume(now time.Time) error {
+	if r.State != RolloutPausedState {
+		return fmt.Errorf("cannot resume a %s rollout", r.State)
+	}
+	r.State = RolloutRunningState
+	r.scheduleNext(now)
+	return nil
+}
+
+// Abort stops a running or paused rollout for good.
+func (r *Rollout) Abort() error {
+	if !r.Active() {
+		return fmt.Errorf("cannot abort a %s rollout", r.State)
+	}
+	r.State = RolloutAbortedState
+	r.NextStepAt = nil
+	return nil
+}
+
+// rolloutGate returns the feature's gate without its actors while its rollout
+// is aborted. Aborting sets the actor percent to 0, which would otherwise make
+// the gate enable every listed actor instead of none.
+func (f *Feature) rolloutGate() *Gate {
+	if f.Gate == nil || f.Rollout == nil || f.Rollout.State != RolloutAbortedState || len(f.Gate.Actors) == 0 {
+		return f.Gate
+	}
+	g := *f.Gate
+	g.Actors = nil
+	return &g
+}
+
+func (r *Rollout) scheduleNext(now time.Time) {
+	if r.Step >= len(r.Steps)-1 {
+		r.State = RolloutCompletedState
+		r.NextStepAt = nil
+		return
+	}
+	d, err := r.Duration()
+	if err != nil {
+		r.NextStepAt = nil
+		return
+	}
+	next := now.Add(d)
+	r.NextStepAt = &next
+}
+
+// String returns a human-readable representation of the rollout, used in audit
+// diffs.
+func (r *Rollout) String() string {
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s every %s (%s, step %d)", joinSteps(r.Steps), r.Interval, r.State, r.Step+1)
+}
+
+func joinSteps(steps []int) string {
+	s := make([]string, len(steps))
+	for i, p := range steps {
+		s[i] = strconv.Itoa(p)
+	}
+	return strings.Join(s, ",")
+}
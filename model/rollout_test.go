@@ -0,0 +1,93 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var rolloutValidateTests = []struct {
+	rollout Rollout
+	valid   bool
+}{
+	{Rollout{Steps: []int{1, 5, 25, 50, 100}, Interval: "24h"}, true},
+	{Rollout{Steps: []int{100}, Interval: "1m"}, true},
+	{Rollout{Interval: "24h"}, false},
+	{Rollout{Steps: []int{0, 100}, Interval: "24h"}, false},
+	{Rollout{Steps: []int{5, 101}, Interval: "24h"}, false},
+	{Rollout{Steps: []int{25, 5}, Interval: "24h"}, false},
+	{Rollout{Steps: []int{1, 5}, Interval: "daily"}, false},
+	{Rollout{Steps: []int{1, 5}, Interval: "-1h"}, false},
+}
+
+func TestRollout_Validate(t *testing.T) {
+	for i, tt := range rolloutValidateTests {
+		if tt.valid {
+			assert.NoError(t, tt.rollout.Validate(), fmt.Sprintf("case %d", i+1))
+		} else {
+			assert.Error(t, tt.rollout.Validate(), fmt.Sprintf("case %d", i+1))
+		}
+	}
+}
+
+func TestRollout_Lifecycle(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	r := &Rollout{Steps: []int{1, 50, 100}, Interval: "1h"}
+
+	r.Start(now)
+	assert.Equal(t, RolloutRunningState, r.State)
+	assert.Equal(t, 1, r.Percent())
+	assert.False(t, r.Due(now.Add(59*time.Minute)))
+	assert.True(t, r.Due(now.Add(time.Hour)))
+
+	now = now.Add(time.Hour)
+	r.Advance(now)
+	assert.Equal(t, 50, r.Percent())
+	assert.Equal(t, now.Add(time.Hour), *r.NextStepAt)
+
+	assert.NoError(t, r.Pause())
+	assert.Error(t, r.Pause())
+	assert.False(t, r.Due(now.Add(24*time.Hour)))
+	assert.Equal(t, 50, r.Percent())
+
+	now = now.Add(24 * time.Hour)
+	assert.NoError(t, r.Resume(now))
+	assert.Equal(t, now.Add(time.Hour), *r.NextStepAt)
+
+	r.Advance(now.Add(time.Hour))
+	assert.Equal(t, RolloutCompletedState, r.State)
+	assert.Equal(t, 100, r.Percent())
+	assert.Nil(t, r.NextStepAt)
+	assert.Error(t, r.Abort())
+}
+
+func TestRollout_Abort(t *testing.T) {
+	r := &Rollout{Steps: []int{1, 50, 100}, Interval: "1h"}
+	r.Start(time.Now())
+	assert.NoError(t, r.Abort())
+	assert.Equal(t, 0, r.Percent())
+	assert.False(t, r.Active())
+	assert.Error(t, r.Resume(time.Now()))
+}
+
+func TestRollout_String(t *testing.T) {
+	r := &Rollout{Steps: []int{1, 5, 25}, Interval: "24h", State: RolloutPausedState, Step: 1}
+	assert.Equal(t, "1,5,25 every 24h (paused, step 2)", r.String())
+
+	var empty *Rollout
+	assert.Equal(t, "", empty.String())
+}
+
+func TestFeature_EnvironmentGateAbortedRollout(t *testing.T) {
+	f := &Feature{
+		Gate:    &Gate{Actors: []string{"one"}, Groups: []string{"beta"}},
+		Rollout: &Rollout{Steps: []int{1, 5}, Interval: "1h", State: RolloutAbortedState},
+	}
+	assert.Equal(t, &Gate{Groups: []string{"beta"}}, f.EnvironmentGate(""))
+	assert.Equal(t, []string{"one"}, f.Gate.Actors, "stored gate was changed")
+
+	f.Rollout.State = RolloutPausedState
+	assert.Equal(t, f.Gate, f.EnvironmentGate(""))
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/robzienert/lever/shared/semverutil"
+	"github.com/robzienert/lever/shared/strutil"
+)
+
+// Rule operators. Equality and list operators compare attribute values as
+// strings, numeric operators parse both sides as floats and semver operators
+// compare both sides as semantic versions.
+const (
+	EqualsOperator                   = "equals"
+	NotEqualsOperator                = "notEquals"
+	InOperator                       = "in"
+	NotInOperator                    = "notIn"
+	RegexOperator                    = "regex"
+	GreaterThanOperator              = "gt"
+	GreaterThanOrEqualOperator       = "gte"
+	LessThanOperator                 = "lt"
+	LessThanOrEqualOperator          = "lte"
+	SemverEqualsOperator             = "semverEquals"
+	SemverGreaterThanOperator        = "semverGt"
+	SemverGreaterThanOrEqualOperator = "semverGte"
+	SemverLessThanOperator           = "semverLt"
+	SemverLessThanOrEqualOperator    = "semverLte"
+)
+
+var listOperators = []string{InOperator, NotInOperator}
+
+var numericOperators = []string{
+	GreaterThanOperator,
+	GreaterThanOrEqualOperator,
+	LessThanOperator,
+	LessThanOrEqualOperator,
+}
+
+var semverOperators = []string{
+	SemverEqualsOperator,
+	SemverGreaterThanOperator,
+	SemverGreaterThanOrEqualOperator,
+	SemverLessThanOperator,
+	SemverLessThanOrEqualOperator,
+}
+
+// Rule matches a single attribute of the evaluation context. List operators
+// use every value, all other operators only use the first.
+//
+// The pattern of a regex rule is compiled once, when the rule is decoded, so
+// that it is not compiled again on every evaluation.
+type Rule struct {
+	Attribute string   `json:"attribute"`
+	Operator  string   `json:"operator"`
+	Values    []string `json:"values"`
+
+	regex *regexp.Regexp
+}
+
+// UnmarshalJSON decodes the rule and compiles its pattern if it is a regex
+// rule. Invalid patterns are left for Validate to report.
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	type rule Rule
+	var v rule
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = Rule(v)
+	if r.Operator == RegexOperator && len(r.Values) > 0 {
+		r.regex, _ = regexp.Compile(r.Values[0])
+	}
+	return nil
+}
+
+// Regexp returns the compiled pattern of a regex rule. Rules that were not
+// decoded from JSON compile their pattern on every call.
+func (r Rule) Regexp() (*regexp.Regexp, error) {
+	if r.regex != nil {
+		return r.regex, nil
+	}
+	if len(r.Values) == 0 {
+		return nil, fmt.Errorf("rule for %s has no values", r.Attribute)
+	}
+	return regexp.Compile(r.Values[0])
+}
+
+// Validate checks that the rule can be evaluated.
+func (r Rule) Validate() error {
+	if r.Attribute == "" {
+		return fmt.Errorf("rule is missing an attribute")
+	}
+	if len(r.Values) == 0 {
+		return fmt.Errorf("rule for %s has no values", r.Attribute)
+	}
+	switch {
+	case r.Operator == EqualsOperator || r.Operator == NotEqualsOperator:
+	case strutil.StringInSlice(r.Operator, listOperators):
+	case r.Operator == RegexOperator:
+		if _, err := r.Regexp(); err != nil {
+			return fmt.Errorf("rule for %s has an invalid regex: %s", r.Attribute, err)
+		}
+	case strutil.StringInSlice(r.Operator, numericOperators):
+		if _, err := strconv.ParseFloat(r.Values[0], 64); err != nil {
+			return fmt.Errorf("rule for %s has a non-numeric value: %s", r.Attribute, r.Values[0])
+		}
+	case strutil.StringInSlice(r.Operator, semverOperators):
+		if _, err := semverutil.Parse(r.Values[0]); err != nil {
+			return fmt.Errorf("rule for %s has an invalid version: %s", r.Attribute, r.Values[0])
+		}
+	default:
+		return fmt.Errorf("rule for %s has an unknown operator: %s", r.Attribute, r.Operator)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the rule, used in audit
+// diffs.
+func (r Rule) String() string {
+	if strutil.StringInSlice(r.Operator, listOperators) {
+		return fmt.Sprintf("%s %s [%s]", r.Attribute, r.Operator, strings.Join(r.Values, ","))
+	}
+	var value string
+	if len(r.Values) > 0 {
+		value = r.Values[0]
+	}
+	return fmt.Sprintf("%s %s %s", r.Attribute, r.Operator, value)
+}
+
+func joinRules(rules []Rule) string {
+	s := make([]string, len(rules))
+	for i, r := range rules {
+		s[i] = r.String()
+	}
+	return strings.Join(s, "; ")
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ruleValidateTests = []struct {
+	rule  Rule
+	valid bool
+}{
+	{Rule{Attribute: "country", Operator: EqualsOperator, Values: []string{"DE"}}, true},
+	{Rule{Attribute: "country", Operator: InOperator, Values: []string{"DE", "FR"}}, true},
+	{Rule{Attribute: "", Operator: EqualsOperator, Values: []string{"DE"}}, false},
+	{Rule{Attribute: "country", Operator: EqualsOperator}, false},
+	{Rule{Attribute: "country", Operator: "like", Values: []string{"DE"}}, false},
+	{Rule{Attribute: "email", Operator: RegexOperator, Values: []string{"("}}, false},
+	{Rule{Attribute: "age", Operator: GreaterThanOperator, Values: []string{"old"}}, false},
+	{Rule{Attribute: "appVersion", Operator: SemverLessThanOperator, Values: []string{"5.x"}}, false},
+	{Rule{Attribute: "appVersion", Operator: SemverLessThanOperator, Values: []string{"v5.2.1"}}, true},
+}
+
+func TestRule_Validate(t *testing.T) {
+	for _, tt := range ruleValidateTests {
+		if tt.valid {
+			assert.NoError(t, tt.rule.Validate(), tt.rule.String())
+		} else {
+			assert.Error(t, tt.rule.Validate(), tt.rule.String())
+		}
+	}
+}
+
+func TestRule_String(t *testing.T) {
+	assert.Equal(t, "country in [DE,FR]", Rule{Attribute: "country", Operator: InOperator, Values: []string{"DE", "FR"}}.String())
+	assert.Equal(t, "appVersion semverGte 5.2.1", Rule{Attribute: "appVersion", Operator: SemverGreaterThanOrEqualOperator, Values: []string{"5.2.1"}}.String())
+}
+
+func TestRule_UnmarshalJSON(t *testing.T) {
+	var rules []Rule
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"attribute": "email", "operator": "regex", "values": ["@example\\.com$"]},
+		{"attribute": "email", "operator": "regex", "values": ["("]}
+	]`), &rules))
+
+	re, err := rules[0].Regexp()
+	assert.NoError(t, err)
+	assert.True(t, re.MatchString("me@example.com"))
+	assert.True(t, re == rules[0].regex, "pattern was compiled again")
+	assert.Error(t, rules[1].Validate())
+}
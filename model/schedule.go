@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule enables a gate from an optional start time until an optional end
+// time. If windows are given, the gate is additionally only enabled while one
+// of the windows is open.
+type Schedule struct {
+	Start   *time.Time `json:"start,omitempty"`
+	End     *time.Time `json:"end,omitempty"`
+	Windows []Window   `json:"windows,omitempty"`
+}
+
+// Window is a recurring time of day, such as weekdays from 09:00 to 17:00. Days
+// are weekday names, like "monday", and default to every day. Times are in
+// 24-hour "15:04" format and the time zone defaults to UTC. A window whose end
+// is before its start spans midnight, and its end cannot equal its start.
+type Window struct {
+	Days     []string `json:"days,omitempty"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	TimeZone string   `json:"timeZone,omitempty"`
+}
+
+// Validate checks that the schedule can be evaluated.
+func (s *Schedule) Validate() error {
+	if s.Start != nil && s.End != nil && !s.End.After(*s.Start) {
+		return fmt.Errorf("schedule end must be after its start")
+	}
+	for _, w := range s.Windows {
+		start, end, err := w.Minutes()
+		if err != nil {
+			return err
+		}
+		if start == end {
+			return fmt.Errorf("window end must not equal its start: %s", w.Start)
+		}
+		if _, err := w.Location(); err != nil {
+			return err
+		}
+		if _, err := w.Weekdays(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the schedule, used in
+// audit diffs.
+func (s *Schedule) String() string {
+	if s == nil {
+		return ""
+	}
+	var parts []string
+	if s.Start != nil || s.End != nil {
+		var start, end string
+		if s.Start != nil {
+			start = s.Start.UTC().Format(time.RFC3339)
+		}
+		if s.End != nil {
+			end = s.End.UTC().Format(time.RFC3339)
+		}
+		parts = append(parts, fmt.Sprintf("%s - %s", start, end))
+	}
+	for _, w := range s.Windows {
+		days := "daily"
+		if len(w.Days) > 0 {
+			days = strings.Join(w.Days, ",")
+		}
+		tz := w.TimeZone
+		if tz == "" {
+			tz = "UTC"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s-%s %s", days, w.Start, w.End, tz))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Minutes returns the start and end of the window as minutes since midnight.
+func (w Window) Minutes() (int, int, error) {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// Location returns the window's time zone.
+func (w Window) Location() (*time.Location, error) {
+	if w.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window time zone: %s", w.TimeZone)
+	}
+	return loc, nil
+}
+
+// Weekdays returns the days the window is open on. An empty slice means every
+// day.
+func (w Window) Weekdays() ([]time.Weekday, error) {
+	days := make([]time.Weekday, 0, len(w.Days))
+	for _, name := range w.Days {
+		day, ok := weekdays[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("invalid window day: %s", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window time: %s", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
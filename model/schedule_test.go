@@ -0,0 +1,47 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_Validate(t *testing.T) {
+	start := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	tests := []struct {
+		schedule Schedule
+		valid    bool
+	}{
+		{Schedule{Start: &start, End: &end}, true},
+		{Schedule{Start: &end, End: &start}, false},
+		{Schedule{Windows: []Window{{Days: []string{"Saturday"}, Start: "09:00", End: "17:00", TimeZone: "America/Chicago"}}}, true},
+		{Schedule{Windows: []Window{{Start: "9am", End: "17:00"}}}, false},
+		{Schedule{Windows: []Window{{Start: "09:00", End: "17:00", TimeZone: "Mars/Olympus_Mons"}}}, false},
+		{Schedule{Windows: []Window{{Days: []string{"caturday"}, Start: "09:00", End: "17:00"}}}, false},
+		{Schedule{Windows: []Window{{Start: "09:00", End: "09:00"}}}, false},
+		{Schedule{Windows: []Window{{Start: "22:00", End: "06:00"}}}, true},
+	}
+	for i, tt := range tests {
+		if tt.valid {
+			assert.NoError(t, tt.schedule.Validate(), fmt.Sprintf("case %d", i+1))
+		} else {
+			assert.Error(t, tt.schedule.Validate(), fmt.Sprintf("case %d", i+1))
+		}
+	}
+}
+
+func TestSchedule_String(t *testing.T) {
+	start := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	s := &Schedule{
+		Start:   &start,
+		Windows: []Window{{Days: []string{"monday", "friday"}, Start: "09:00", End: "17:00", TimeZone: "Europe/Berlin"}},
+	}
+	assert.Equal(t, "2026-11-01T00:00:00Z - ; monday,friday 09:00-17:00 Europe/Berlin", s.String())
+
+	var empty *Schedule
+	assert.Equal(t, "", empty.String())
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Segment is a named, reusable set of actors, groups and attribute rules that
+// gates can reference instead of repeating them. A context is in the segment
+// if any of its actors or groups are listed, or if every rule matches.
+type Segment struct {
+	Name        string    `json:"name" binding:"required"`
+	Actors      []string  `json:"actors,omitempty"`
+	Groups      []string  `json:"groups,omitempty"`
+	Rules       []Rule    `json:"rules,omitempty"`
+	DateCreated time.Time `json:"dateCreated"`
+	LastUpdated time.Time `json:"lastUpdated"`
+}
+
+// SegmentLookup returns a segment by name, or nil if it does not exist.
+type SegmentLookup func(name string) (*Segment, error)
+
+// Copy returns a copy of the segment whose actors, groups and rules can be
+// changed without affecting the original.
+func (s *Segment) Copy() *Segment {
+	c := *s
+	c.Actors = append([]string(nil), s.Actors...)
+	c.Groups = append([]string(nil), s.Groups...)
+	c.Rules = append([]Rule(nil), s.Rules...)
+	return &c
+}
+
+// Validate checks that the segment can be evaluated.
+func (s *Segment) Validate() error {
+	if len(s.Actors) == 0 && len(s.Groups) == 0 && len(s.Rules) == 0 {
+		return fmt.Errorf("segment %s has no actors, groups or rules", s.Name)
+	}
+	for _, r := range s.Rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Diff returns a flatmap diff of two segments, which can be used for auditing.
+// It is expected that Name does not change.
+func (s *Segment) Diff(b *Segment) Fields {
+	d := make(map[string]string, 0)
+	sActors := strings.Join(s.Actors, ",")
+	bActors := strings.Join(b.Actors, ",")
+	if sActors != bActors {
+		d["actors"] = diffValue(sActors, bActors)
+	}
+	sGroups := strings.Join(s.Groups, ",")
+	bGroups := strings.Join(b.Groups, ",")
+	if sGroups != bGroups {
+		d["groups"] = diffValue(sGroups, bGroups)
+	}
+	sRules := joinRules(s.Rules)
+	bRules := joinRules(b.Rules)
+	if sRules != bRules {
+		d["rules"] = diffValue(sRules, bRules)
+	}
+	return d
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var segmentValidateTests = []struct {
+	segment Segment
+	valid   bool
+}{
+	{Segment{Name: "beta", Actors: []string{"one"}}, true},
+	{Segment{Name: "beta", Groups: []string{"staff"}}, true},
+	{Segment{Name: "beta", Rules: []Rule{{Attribute: "country", Operator: EqualsOperator, Values: []string{"DE"}}}}, true},
+	{Segment{Name: "beta"}, false},
+	{Segment{Name: "beta", Rules: []Rule{{Attribute: "country", Operator: "like", Values: []string{"DE"}}}}, false},
+}
+
+func TestSegment_Validate(t *testing.T) {
+	for i, tt := range segmentValidateTests {
+		if tt.valid {
+			assert.NoError(t, tt.segment.Validate(), fmt.Sprintf("case %d", i+1))
+		} else {
+			assert.Error(t, tt.segment.Validate(), fmt.Sprintf("case %d", i+1))
+		}
+	}
+}
+
+func TestSegment_Diff(t *testing.T) {
+	s1 := Segment{Name: "beta", Actors: []string{"one"}, Groups: []string{"staff"}}
+	s2 := Segment{
+		Name:   "beta",
+		Actors: []string{"one", "two"},
+		Groups: []string{"staff"},
+		Rules:  []Rule{{Attribute: "plan", Operator: EqualsOperator, Values: []string{"pro"}}},
+	}
+
+	fields := s1.Diff(&s2)
+	assert.Equal(t, "one -> one,two", fields["actors"], "actors did not match")
+	assert.Equal(t, "NO_VALUE -> plan equals pro", fields["rules"], "rules did not match")
+	assert.NotContains(t, fields, "groups")
+}
+
+func TestSegment_Copy(t *testing.T) {
+	s := &Segment{Name: "beta", Actors: []string{"one"}, Groups: []string{"staff"}}
+	c := s.Copy()
+	assert.Equal(t, s, c)
+
+	c.Actors[0] = "two"
+	c.Groups = append(c.Groups, "admins")
+	assert.Equal(t, []string{"one"}, s.Actors)
+	assert.Equal(t, []string{"staff"}, s.Groups)
+}
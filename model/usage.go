@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// EnabledOutcome and DisabledOutcome are the outcomes of evaluations that were
+// not assigned a variant. Evaluations that were assigned a variant have the
+// variant's name as their outcome.
+const (
+	EnabledOutcome  = "enabled"
+	DisabledOutcome = "disabled"
+)
+
+// Usage counts the evaluations of a feature with a single outcome by a single
+// OAuth client.
+type Usage struct {
+	Namespace     string    `json:"namespace,omitempty"`
+	Key           string    `json:"key"`
+	ClientID      string    `json:"clientId"`
+	Outcome       string    `json:"outcome"`
+	Count         int64     `json:"count"`
+	LastEvaluated time.Time `json:"lastEvaluated"`
+}
+
+// ID returns the evaluated feature's key, prefixed by its namespace if it has
+// one.
+func (u *Usage) ID() string {
+	return featureID(u.Namespace, u.Key)
+}
+
+// Add merges the counts of other into the usage, keeping the latest evaluation
+// time. Both must count the same feature, client and outcome.
+func (u *Usage) Add(other *Usage) {
+	u.Count += other.Count
+	if other.LastEvaluated.After(u.LastEvaluated) {
+		u.LastEvaluated = other.LastEvaluated
+	}
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// BoolValueType values are "true" or "false".
+//
+// IntValueType values are 64 bit integers.
+//
+// FloatValueType values are 64 bit floating point numbers.
+//
+// StringValueType values are used as is.
+//
+// JSONValueType values are any JSON document. If the feature has a Schema,
+// values must also be valid against it.
+const (
+	BoolValueType   = "bool"
+	IntValueType    = "int"
+	FloatValueType  = "float"
+	StringValueType = "string"
+	JSONValueType   = "json"
+)
+
+// legacyValueTypes maps the Java class names that were used as feature types
+// before values were typed.
+var legacyValueTypes = map[string]string{
+	"java.lang.Boolean": BoolValueType,
+	"java.lang.Byte":    IntValueType,
+	"java.lang.Short":   IntValueType,
+	"java.lang.Integer": IntValueType,
+	"java.lang.Long":    IntValueType,
+	"java.lang.Float":   FloatValueType,
+	"java.lang.Double":  FloatValueType,
+	"java.lang.String":  StringValueType,
+}
+
+// ValueType returns the feature's value type, mapping legacy Java type names,
+// and whether it is supported.
+func (f *Feature) ValueType() (string, bool) {
+	if t, ok := legacyValueTypes[f.Type]; ok {
+		return t, true
+	}
+	switch f.Type {
+	case BoolValueType, IntValueType, FloatValueType, StringValueType, JSONValueType:
+		return f.Type, true
+	}
+	return "", false
+}
+
+// DecodeValue decodes a value of the feature's type. JSON values are returned
+// as a json.RawMessage.
+func (f *Feature) DecodeValue(value string) (interface{}, error) {
+	t, ok := f.ValueType()
+	if !ok {
+		return nil, fmt.Errorf("unsupported feature type: %s", f.Type)
+	}
+	switch t {
+	case BoolValueType:
+		switch value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	case IntValueType:
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i, nil
+		}
+	case FloatValueType:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n, nil
+		}
+	case StringValueType:
+		return value, nil
+	case JSONValueType:
+		if json.Valid([]byte(value)) {
+			return json.RawMessage(value), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s value: %s", t, value)
+}
+
+// validateValues checks that the feature's value, off value and the values of
+// all of its variants can be decoded, and are valid against its schema.
+func (f *Feature) validateValues() error {
+	t, ok := f.ValueType()
+	if !ok {
+		return fmt.Errorf("unsupported feature type: %s", f.Type)
+	}
+
+	var schema *gojsonschema.Schema
+	if len(f.Schema) > 0 {
+		if t != JSONValueType {
+			return fmt.Errorf("only %s features can have a schema", JSONValueType)
+		}
+		var err error
+		schema, err = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(f.Schema))
+		if err != nil {
+			return fmt.Errorf("invalid schema: %s", err)
+		}
+	}
+
+	values := []string{f.Value}
+	if f.OffValue != "" {
+		values = append(values, f.OffValue)
+	}
+	for _, v := range f.Variants {
+		values = append(values, v.Value)
+	}
+	for _, value := range values {
+		if _, err := f.DecodeValue(value); err != nil {
+			return err
+		}
+		if schema == nil {
+			continue
+		}
+		result, err := schema.Validate(gojsonschema.NewStringLoader(value))
+		if err != nil {
+			return err
+		}
+		if !result.Valid() {
+			errs := make([]string, len(result.Errors()))
+			for i, e := range result.Errors() {
+				errs[i] = e.String()
+			}
+			return fmt.Errorf("value does not match schema: %s", strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var valueTypeTests = []struct {
+	featureType string
+	expected    string
+	ok          bool
+}{
+	{"bool", BoolValueType, true},
+	{"json", JSONValueType, true},
+	{"java.lang.Boolean", BoolValueType, true},
+	{"java.lang.Long", IntValueType, true},
+	{"java.lang.Double", FloatValueType, true},
+	{"java.lang.String", StringValueType, true},
+	{"java.util.Date", "", false},
+	{"", "", false},
+}
+
+func TestFeature_ValueType(t *testing.T) {
+	for i, tt := range valueTypeTests {
+		actual, ok := (&Feature{Type: tt.featureType}).ValueType()
+		assert.Equal(t, tt.expected, actual, fmt.Sprintf("case %d", i+1))
+		assert.Equal(t, tt.ok, ok, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+var decodeValueTests = []struct {
+	featureType string
+	value       string
+	expected    interface{}
+	valid       bool
+}{
+	{BoolValueType, "true", true, true},
+	{BoolValueType, "false", false, true},
+	{BoolValueType, "yes", nil, false},
+	{IntValueType, "42", int64(42), true},
+	{IntValueType, "4.2", nil, false},
+	{FloatValueType, "4.2", 4.2, true},
+	{FloatValueType, "four", nil, false},
+	{StringValueType, "", "", true},
+	{JSONValueType, `{"color":"blue"}`, json.RawMessage(`{"color":"blue"}`), true},
+	{JSONValueType, `{"color":`, nil, false},
+	{"java.util.Date", "today", nil, false},
+}
+
+func TestFeature_DecodeValue(t *testing.T) {
+	for i, tt := range decodeValueTests {
+		actual, err := (&Feature{Type: tt.featureType}).DecodeValue(tt.value)
+		assert.Equal(t, tt.expected, actual, fmt.Sprintf("case %d", i+1))
+		assert.Equal(t, tt.valid, err == nil, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+const testSchema = `{"type":"object","properties":{"color":{"type":"string"}},"required":["color"]}`
+
+var validateValuesTests = []struct {
+	feature Feature
+	valid   bool
+}{
+	{Feature{Type: "java.lang.Boolean", Value: "true"}, true},
+	{Feature{Type: "java.lang.Boolean", Value: "1"}, false},
+	{Feature{Type: "unknown", Value: "true"}, false},
+	{Feature{Type: BoolValueType, Value: "true", OffValue: "false"}, true},
+	{Feature{Type: BoolValueType, Value: "true", OffValue: "no"}, false},
+	{Feature{Type: JSONValueType, Value: `{"color":"blue"}`, OffValue: `{}`, Schema: json.RawMessage(testSchema)}, false},
+	{Feature{Type: IntValueType, Value: "1", Variants: []Variant{{Name: "a", Weight: 1, Value: "2"}}}, true},
+	{Feature{Type: IntValueType, Value: "1", Variants: []Variant{{Name: "a", Weight: 1, Value: "two"}}}, false},
+	{Feature{Type: JSONValueType, Value: `{"color":"blue"}`, Schema: json.RawMessage(testSchema)}, true},
+	{Feature{Type: JSONValueType, Value: `{"size":1}`, Schema: json.RawMessage(testSchema)}, false},
+	{Feature{Type: JSONValueType, Value: `{"color":"blue"}`, Schema: json.RawMessage(testSchema), Variants: []Variant{{Name: "a", Weight: 1, Value: `{"color":1}`}}}, false},
+	{Feature{Type: JSONValueType, Value: `{}`, Schema: json.RawMessage(`{"type":"nope"}`)}, false},
+	{Feature{Type: StringValueType, Value: "blue", Schema: json.RawMessage(`{"type":"string"}`)}, false},
+}
+
+func TestFeature_Validate_Values(t *testing.T) {
+	for i, tt := range validateValuesTests {
+		tt.feature.Gate = &Gate{}
+		assert.Equal(t, tt.valid, tt.feature.Validate() == nil, fmt.Sprintf("case %d", i+1))
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Variant is a named value of a multivariate feature. Enabled actors are
+// assigned a variant in proportion to its weight relative to the total weight
+// of all variants.
+type Variant struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+	Value  string `json:"value"`
+}
+
+// String returns a human-readable representation of the variant, used in
+// audit diffs.
+func (v Variant) String() string {
+	return fmt.Sprintf("%s:%d:%s", v.Name, v.Weight, v.Value)
+}
+
+func validateVariants(variants []Variant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	names := make(map[string]bool, len(variants))
+	total := 0
+	for _, v := range variants {
+		if v.Name == "" {
+			return fmt.Errorf("variant is missing a name")
+		}
+		if names[v.Name] {
+			return fmt.Errorf("duplicate variant: %s", v.Name)
+		}
+		if v.Weight < 0 {
+			return fmt.Errorf("variant %s has a negative weight", v.Name)
+		}
+		names[v.Name] = true
+		total += v.Weight
+	}
+	if total == 0 {
+		return fmt.Errorf("variants must have a total weight above zero")
+	}
+	return nil
+}
+
+func joinVariants(variants []Variant) string {
+	s := make([]string, len(variants))
+	for i, v := range variants {
+		s[i] = v.String()
+	}
+	return strings.Join(s, ", ")
+}
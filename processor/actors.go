@@ -1,17 +1,15 @@
 package processor
 
 import (
-	"strings"
-
 	"github.com/robzienert/lever/model"
 	"github.com/robzienert/lever/shared/strutil"
 )
 
-func actorsProcessor(g *model.Gate, value string) bool {
-	for _, actor := range strings.Split(value, ",") {
+func actorsProcessor(g *model.Gate, ctx *Context) Match {
+	for _, actor := range ctx.Actors {
 		if strutil.StringInSlice(actor, g.Actors) {
-			return true
+			return Match{Matched: true, Actor: actor}
 		}
 	}
-	return false
+	return Match{}
 }
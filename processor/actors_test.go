@@ -31,7 +31,7 @@ var actorsTests = []struct {
 
 func TestActorsProcessor(t *testing.T) {
 	for _, tt := range actorsTests {
-		actual := actorsProcessor(tt.gate, tt.value)
+		actual := actorsProcessor(tt.gate, NewContext(tt.value, "")).Matched
 		if tt.expected {
 			assert.True(t, actual)
 		} else {
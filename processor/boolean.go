@@ -2,6 +2,6 @@ package processor
 
 import "github.com/robzienert/lever/model"
 
-func booleanProcessor(g *model.Gate, value string) bool {
-	return g.Value == "true"
+func booleanProcessor(g *model.Gate, ctx *Context) Match {
+	return Match{Matched: g.Value == "true"}
 }
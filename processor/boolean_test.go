@@ -31,7 +31,7 @@ var booleanTests = []struct {
 
 func TestBooleanProcessor(t *testing.T) {
 	for _, tt := range booleanTests {
-		actual := booleanProcessor(tt.gate, tt.value)
+		actual := booleanProcessor(tt.gate, NewContext(tt.value, "")).Matched
 		if tt.expected {
 			assert.True(t, actual)
 		} else {
@@ -0,0 +1,36 @@
+package processor
+
+import "github.com/spaolacci/murmur3"
+
+// Bucket returns the bucket, from 0 to 99, an actor falls into for percentage
+// based gates. The actor is salted with the gate's seed as "<seed>:<actor>" so
+// that features rolled out to the same percentage do not enable the same
+// actors. Gates without a seed use the legacy, unsalted hash of the actor.
+//
+// This is the reference implementation for SDKs; see testdata/buckets.json.
+func Bucket(seed string, actor string) uint32 {
+	if seed == "" {
+		return murmur3.Sum32([]byte(actor)) % 100
+	}
+	return murmur3.Sum32([]byte(seed+":"+actor)) % 100
+}
+
+// withinPercentage returns whether the bucket falls within the percentage.
+// Legacy, unsalted percentOfActors gates keep their original inclusive bound so
+// that existing assignments do not change.
+func withinPercentage(seed string, bucket uint32, percent int) bool {
+	if seed == "" {
+		return bucket <= uint32(percent)
+	}
+	return bucket < uint32(percent)
+}
+
+// percentageMatch buckets the actor for the percentOfActors gate type.
+func percentageMatch(seed string, actor string, percent int) Match {
+	bucket := Bucket(seed, actor)
+	return Match{
+		Matched: withinPercentage(seed, bucket, percent),
+		Actor:   actor,
+		Bucket:  &bucket,
+	}
+}
@@ -0,0 +1,62 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The vectors in testdata/buckets.json are published for SDKs in other
+// languages to verify their bucketing against.
+func TestBucket_Vectors(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/buckets.json")
+	assert.NoError(t, err)
+
+	var vectors []struct {
+		Seed   string `json:"seed"`
+		Actor  string `json:"actor"`
+		Bucket uint32 `json:"bucket"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &vectors))
+	assert.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		assert.Equal(t, v.Bucket, Bucket(v.Seed, v.Actor), fmt.Sprintf("seed %q, actor %q", v.Seed, v.Actor))
+	}
+}
+
+func TestBucket_SaltedByFeature(t *testing.T) {
+	differs := false
+	for i := 0; i < 100; i++ {
+		actor := fmt.Sprintf("actor-%d", i)
+		if Bucket("mobile.ios/fooFeature", actor) != Bucket("mobile.ios/barFeature", actor) {
+			differs = true
+			break
+		}
+	}
+	assert.True(t, differs, "salted buckets should differ between features")
+}
+
+func TestWithinPercentage_Bounds(t *testing.T) {
+	for bucket := uint32(0); bucket < 100; bucket++ {
+		assert.False(t, withinPercentage("fooFeature", bucket, 0))
+		assert.True(t, withinPercentage("fooFeature", bucket, 100))
+		assert.Equal(t, bucket < 50, withinPercentage("fooFeature", bucket, 50))
+		assert.Equal(t, bucket <= 50, withinPercentage("", bucket, 50), "legacy bound is inclusive")
+	}
+}
+
+func TestPercentageMatch(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		actor := fmt.Sprintf("actor-%d", i)
+		m := percentageMatch("fooFeature", actor, 50)
+		assert.Equal(t, actor, m.Actor)
+		if assert.NotNil(t, m.Bucket) {
+			assert.Equal(t, Bucket("fooFeature", actor), *m.Bucket)
+			assert.Equal(t, *m.Bucket < 50, m.Matched)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package processor
+
+import "github.com/robzienert/lever/model"
+
+// cidrsProcessor enables the gate if the caller's IP is in any of its CIDR
+// ranges. Contexts without an IP never match.
+func cidrsProcessor(g *model.Gate, ctx *Context) Match {
+	if ctx.IP == nil {
+		return Match{}
+	}
+	for i, n := range g.Networks() {
+		if n != nil && n.Contains(ctx.IP) {
+			return Match{Matched: true, CIDR: g.CIDRs[i]}
+		}
+	}
+	return Match{}
+}
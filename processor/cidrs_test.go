@@ -0,0 +1,30 @@
+package processor
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/robzienert/lever/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var cidrsTests = []struct {
+	cidrs    []string
+	ip       string
+	expected Match
+}{
+	{[]string{"10.0.0.0/8", "192.168.1.0/24"}, "192.168.1.20", Match{Matched: true, CIDR: "192.168.1.0/24"}},
+	{[]string{"10.0.0.0/8", "192.168.1.0/24"}, "192.168.2.20", Match{}},
+	{[]string{"10.0.0.0/8"}, "", Match{}},
+	{[]string{"2001:db8::/32"}, "2001:db8::7", Match{Matched: true, CIDR: "2001:db8::/32"}},
+	{[]string{"10.0.0.0/8"}, "::ffff:10.1.2.3", Match{Matched: true, CIDR: "10.0.0.0/8"}},
+}
+
+func TestCIDRsProcessor(t *testing.T) {
+	for i, tt := range cidrsTests {
+		ctx := NewContext("", "")
+		ctx.IP = net.ParseIP(tt.ip)
+		assert.Equal(t, tt.expected, cidrsProcessor(&model.Gate{CIDRs: tt.cidrs}, ctx), fmt.Sprintf("case %d", i+1))
+	}
+}
@@ -0,0 +1,79 @@
+package processor
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/shared/strutil"
+)
+
+// Context is everything known about the subject of a gate evaluation.
+//
+// Clock is used by time based gates and defaults to the current time. It can
+// be replaced to evaluate a gate at a fixed point in time.
+//
+// FeatureLookup resolves the features that prerequisites refer to. It is
+// required to evaluate features with prerequisites.
+//
+// SegmentLookup resolves the segments that gates refer to. It is required to
+// evaluate segment gates. Segments are only looked up once per Context.
+//
+// Environment selects the gate settings that features, and their
+// prerequisites, are evaluated with.
+//
+// IP is the caller's IP, evaluated by CIDR gates.
+type Context struct {
+	Actors        []string
+	Groups        []string
+	Attributes    map[string]string
+	IP            net.IP
+	Environment   string
+	Clock         func() time.Time
+	FeatureLookup model.FeatureLookup
+	SegmentLookup model.SegmentLookup
+
+	segments map[string]*model.Segment
+	// seed is the default bucketing seed of the feature being evaluated, if
+	// the gate is evaluated for a feature.
+	seed string
+}
+
+// NewContext creates a Context from comma-delimited actors and groups, as
+// they are passed via query strings.
+func NewContext(actors string, groups string) *Context {
+	return &Context{
+		Actors: strutil.SplitList(actors),
+		Groups: strutil.SplitList(groups),
+	}
+}
+
+// resolveSegments looks up every named segment that has not been resolved by
+// this Context yet. Segments that do not exist are resolved as nil.
+func (c *Context) resolveSegments(names []string) error {
+	if c.segments == nil {
+		c.segments = make(map[string]*model.Segment)
+	}
+	for _, name := range names {
+		if _, ok := c.segments[name]; ok {
+			continue
+		}
+		if c.SegmentLookup == nil {
+			return errors.New("no segment lookup to resolve segments")
+		}
+		s, err := c.SegmentLookup(name)
+		if err != nil {
+			return err
+		}
+		c.segments[name] = s
+	}
+	return nil
+}
+
+func (c *Context) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock()
+}
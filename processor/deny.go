@@ -0,0 +1,22 @@
+package processor
+
+import (
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/shared/strutil"
+)
+
+// denied matches the first actor or group of the context that is on one of the
+// gate's deny lists.
+func denied(g *model.Gate, ctx *Context) Match {
+	for _, actor := range ctx.Actors {
+		if strutil.StringInSlice(actor, g.DenyActors) {
+			return Match{Matched: true, Actor: actor}
+		}
+	}
+	for _, group := range ctx.Groups {
+		if strutil.StringInSlice(group, g.DenyGroups) {
+			return Match{Matched: true, Group: group}
+		}
+	}
+	return Match{}
+}
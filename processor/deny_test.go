@@ -0,0 +1,31 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/robzienert/lever/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var denyTests = []struct {
+	gate     *model.Gate
+	ctx      *Context
+	expected bool
+}{
+	{&model.Gate{Value: "true", DenyActors: []string{"broken"}}, NewContext("broken", ""), false},
+	{&model.Gate{Value: "true", DenyActors: []string{"broken"}}, NewContext("fine", ""), true},
+	{&model.Gate{Value: "true", DenyActors: []string{"broken"}}, NewContext("", ""), true},
+	{&model.Gate{Actors: []string{"one", "two"}, DenyActors: []string{"two"}}, NewContext("one,two", ""), false},
+	{&model.Gate{Groups: []string{"beta"}, DenyGroups: []string{"enterprise"}}, NewContext("", "beta,enterprise"), false},
+	{&model.Gate{Groups: []string{"beta"}, DenyGroups: []string{"enterprise"}}, NewContext("", "beta"), true},
+	{&model.Gate{PercentOfEveryone: 100, DenyGroups: []string{"enterprise"}}, NewContext("one", "enterprise"), false},
+}
+
+func TestProcessGate_Deny(t *testing.T) {
+	for i, tt := range denyTests {
+		actual, err := ProcessGate(tt.gate, tt.ctx)
+		assert.NoError(t, err, fmt.Sprintf("case %d", i+1))
+		assert.Equal(t, tt.expected, actual.Enabled, fmt.Sprintf("case %d", i+1))
+	}
+}
@@ -0,0 +1,75 @@
+package processor
+
+import "github.com/robzienert/lever/model"
+
+// The expression processor evaluates other gate types, so it is added to the
+// map after it has been initialized.
+func init() {
+	gateProcessorMap[model.ExpressionGateType] = expressionProcessor
+}
+
+// expressionProcessor enables the gate if its expression is true. The match
+// includes what the expression's gates matched on.
+func expressionProcessor(g *model.Gate, ctx *Context) Match {
+	return evaluateExpression(g.Expression, g.Seed, ctx)
+}
+
+func evaluateExpression(e *model.Expression, seed string, ctx *Context) Match {
+	switch {
+	case e.And != nil:
+		var m Match
+		for i := range e.And {
+			o := evaluateExpression(&e.And[i], seed, ctx)
+			if !o.Matched {
+				return Match{}
+			}
+			m = mergeMatch(m, o)
+		}
+		return m
+	case e.Or != nil:
+		for i := range e.Or {
+			if m := evaluateExpression(&e.Or[i], seed, ctx); m.Matched {
+				return m
+			}
+		}
+		return Match{}
+	case e.Not != nil:
+		return Match{Matched: !evaluateExpression(e.Not, seed, ctx).Matched}
+	case e.Gate != nil:
+		g := *e.Gate
+		if g.Seed == "" {
+			g.Seed = seed
+		}
+		for _, gt := range g.Types() {
+			f := gateProcessorFor(gt)
+			if f == nil {
+				continue
+			}
+			if m := f(&g, ctx); m.Matched {
+				return m
+			}
+		}
+	}
+	return Match{}
+}
+
+// mergeMatch fills in what b matched on where a has nothing.
+func mergeMatch(a Match, b Match) Match {
+	a.Matched = b.Matched
+	if a.Actor == "" {
+		a.Actor = b.Actor
+	}
+	if a.Group == "" {
+		a.Group = b.Group
+	}
+	if a.Segment == "" {
+		a.Segment = b.Segment
+	}
+	if a.CIDR == "" {
+		a.CIDR = b.CIDR
+	}
+	if a.Bucket == nil {
+		a.Bucket = b.Bucket
+	}
+	return a
+}
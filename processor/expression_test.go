@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/robzienert/lever/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// actors list OR (group staff AND NOT country=CN)
+var testExpression = &model.Expression{Or: []model.Expression{
+	{Gate: &model.Gate{Actors: []string{"one"}}},
+	{And: []model.Expression{
+		{Gate: &model.Gate{Groups: []string{"staff"}}},
+		{Not: &model.Expression{Gate: &model.Gate{Rules: []model.Rule{
+			{Attribute: "country", Operator: model.EqualsOperator, Values: []string{"CN"}},
+		}}}},
+	}},
+}}
+
+var expressionTests = []struct {
+	actors     string
+	groups     string
+	attributes map[string]string
+	expected   Match
+}{
+	{"one", "", map[string]string{"country": "CN"}, Match{Matched: true, Actor: "one"}},
+	{"two", "staff", map[string]string{"country": "DE"}, Match{Matched: true, Group: "staff"}},
+	{"two", "staff", map[string]string{"country": "CN"}, Match{}},
+	{"two", "", map[string]string{"country": "DE"}, Match{}},
+}
+
+func TestExpressionProcessor(t *testing.T) {
+	g := &model.Gate{Expression: testExpression}
+	for i, tt := range expressionTests {
+		ctx := NewContext(tt.actors, tt.groups)
+		ctx.Attributes = tt.attributes
+		assert.Equal(t, tt.expected, expressionProcessor(g, ctx), fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestExpressionProcessor_InheritsSeed(t *testing.T) {
+	g := &model.Gate{
+		Seed: "fooFeature",
+		Expression: &model.Expression{And: []model.Expression{
+			{Gate: &model.Gate{Groups: []string{"beta"}}},
+			{Gate: &model.Gate{PercentOfEveryone: 100}},
+		}},
+	}
+	m := expressionProcessor(g, NewContext("robzienert", "beta"))
+	assert.Equal(t, Match{Matched: true, Actor: "robzienert", Group: "beta", Bucket: bucketRef(77)}, m)
+
+	m = expressionProcessor(g, NewContext("robzienert", "alpha"))
+	assert.False(t, m.Matched)
+}
+
+func TestProcessGate_ExpressionSegments(t *testing.T) {
+	g := &model.Gate{Expression: &model.Expression{Not: &model.Expression{Gate: &model.Gate{Segments: []string{"beta"}}}}}
+	for actor, expected := range map[string]bool{"one": false, "three": true} {
+		ctx := NewContext(actor, "")
+		ctx.SegmentLookup = func(name string) (*model.Segment, error) {
+			return testSegments[name], nil
+		}
+		result, err := ProcessGate(g, ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result.Enabled, actor)
+	}
+}
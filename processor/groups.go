@@ -1,17 +1,15 @@
 package processor
 
 import (
-	"strings"
-
 	"github.com/robzienert/lever/model"
 	"github.com/robzienert/lever/shared/strutil"
 )
 
-func groupsProcessor(g *model.Gate, value string) bool {
-	for _, group := range strings.Split(value, ",") {
+func groupsProcessor(g *model.Gate, ctx *Context) Match {
+	for _, group := range ctx.Groups {
 		if strutil.StringInSlice(group, g.Groups) {
-			return true
+			return Match{Matched: true, Group: group}
 		}
 	}
-	return false
+	return Match{}
 }
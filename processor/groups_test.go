@@ -31,7 +31,7 @@ var groupsTests = []struct {
 
 func TestGroupsProcessor(t *testing.T) {
 	for _, tt := range groupsTests {
-		actual := groupsProcessor(tt.gate, tt.value)
+		actual := groupsProcessor(tt.gate, NewContext("", tt.value)).Matched
 		if tt.expected {
 			assert.True(t, actual)
 		} else {
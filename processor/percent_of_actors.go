@@ -1,21 +1,23 @@
 package processor
 
 import (
-	"strings"
-
 	"github.com/robzienert/lever/model"
 	"github.com/robzienert/lever/shared/strutil"
-	"github.com/spaolacci/murmur3"
 )
 
-func percentOfActorsProcessor(g *model.Gate, value string) bool {
-	for _, actor := range strings.Split(value, ",") {
+func percentOfActorsProcessor(g *model.Gate, ctx *Context) Match {
+	var miss Match
+	for _, actor := range ctx.Actors {
 		if !strutil.StringInSlice(actor, g.Actors) {
 			continue
 		}
-		if murmur3.Sum32([]byte(actor))%100 <= uint32(g.ActorPercent) {
-			return true
+		m := percentageMatch(g.Seed, actor, g.ActorPercent)
+		if m.Matched {
+			return m
+		}
+		if miss.Bucket == nil {
+			miss = m
 		}
 	}
-	return false
+	return miss
 }
@@ -37,7 +37,7 @@ var percentOfActorsTests = []struct {
 
 func TestPercentOfActorsProcessor(t *testing.T) {
 	for i, tt := range percentOfActorsTests {
-		actual := percentOfActorsProcessor(tt.gate, tt.value)
+		actual := percentOfActorsProcessor(tt.gate, NewContext(tt.value, "")).Matched
 		if tt.expected {
 			assert.True(t, actual, fmt.Sprintf("case %d", i+1))
 		} else {
@@ -0,0 +1,25 @@
+package processor
+
+import "github.com/robzienert/lever/model"
+
+// percentOfEveryoneProcessor buckets every actor. Unlike percentOfActors, it
+// has no legacy behaviour to keep: gates without a seed are salted with the
+// evaluated feature's default seed, and the bound is always exclusive.
+func percentOfEveryoneProcessor(g *model.Gate, ctx *Context) Match {
+	seed := g.Seed
+	if seed == "" {
+		seed = ctx.seed
+	}
+	var miss Match
+	for _, actor := range ctx.Actors {
+		bucket := Bucket(seed, actor)
+		m := Match{Matched: bucket < uint32(g.PercentOfEveryone), Actor: actor, Bucket: &bucket}
+		if m.Matched {
+			return m
+		}
+		if miss.Bucket == nil {
+			miss = m
+		}
+	}
+	return miss
+}
@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/robzienert/lever/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var percentOfEveryoneTests = []struct {
+	gate     *model.Gate
+	value    string
+	expected bool
+}{
+	{
+		&model.Gate{PercentOfEveryone: 100, Seed: "fooFeature"},
+		"",
+		false,
+	},
+	{
+		&model.Gate{PercentOfEveryone: 100, Seed: "fooFeature"},
+		"anyone",
+		true,
+	},
+	{
+		&model.Gate{PercentOfEveryone: 14, Seed: ""},
+		"robzienert",
+		false,
+	},
+	{
+		&model.Gate{PercentOfEveryone: 15, Seed: ""},
+		"robzienert",
+		true,
+	},
+	{
+		&model.Gate{PercentOfEveryone: 77, Seed: "fooFeature"},
+		"robzienert",
+		false,
+	},
+	{
+		&model.Gate{PercentOfEveryone: 78, Seed: "fooFeature"},
+		"robzienert",
+		true,
+	},
+}
+
+func TestPercentOfEveryoneProcessor(t *testing.T) {
+	for i, tt := range percentOfEveryoneTests {
+		actual := percentOfEveryoneProcessor(tt.gate, NewContext(tt.value, "")).Matched
+		if tt.expected {
+			assert.True(t, actual, fmt.Sprintf("case %d", i+1))
+		} else {
+			assert.False(t, actual, fmt.Sprintf("case %d", i+1))
+		}
+	}
+}
+
+func TestPercentOfEveryoneProcessor_Distribution(t *testing.T) {
+	enabled := 0
+	for i := 0; i < 10000; i++ {
+		if percentOfEveryoneProcessor(&model.Gate{PercentOfEveryone: 5, Seed: "fooFeature"}, NewContext(fmt.Sprintf("actor-%d", i), "")).Matched {
+			enabled++
+		}
+	}
+	assert.InDelta(t, 500, enabled, 100)
+}
+
+func TestPercentOfEveryoneProcessor_DefaultSeed(t *testing.T) {
+	// "robzienert" falls into bucket 77 for the seed "fooFeature", and into
+	// bucket 14 without a seed.
+	for i, tt := range []struct {
+		percent  int
+		expected bool
+	}{
+		{15, false},
+		{77, false},
+		{78, true},
+	} {
+		f := &model.Feature{Key: "fooFeature", Gate: &model.Gate{PercentOfEveryone: tt.percent}}
+		e, err := ProcessFeature(f, NewContext("robzienert", ""))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, e.Enabled, fmt.Sprintf("case %d", i+1))
+	}
+}
@@ -6,6 +6,6 @@ import (
 	"github.com/robzienert/lever/model"
 )
 
-func percentOfTimeProcessor(g *model.Gate, value string) bool {
-	return rand.Intn(100) >= g.PercentOfTime
+func percentOfTimeProcessor(g *model.Gate, ctx *Context) Match {
+	return Match{Matched: rand.Intn(100) >= g.PercentOfTime}
 }
@@ -0,0 +1,39 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/robzienert/lever/model"
+)
+
+// unmetPrerequisite evaluates each prerequisite feature for the same context
+// and returns the ID of the first one not in its required state, or an empty
+// string if all are met. Features that do not exist are never enabled.
+func unmetPrerequisite(prerequisites []model.Prerequisite, ctx *Context, visiting map[string]bool) (string, error) {
+	if ctx.FeatureLookup == nil {
+		return "", errors.New("no feature lookup to resolve prerequisites")
+	}
+	for _, p := range prerequisites {
+		if visiting[p.ID()] {
+			return "", fmt.Errorf("prerequisite cycle at feature: %s", p.ID())
+		}
+		f, err := ctx.FeatureLookup(p.Namespace, p.Key)
+		if err != nil {
+			return "", err
+		}
+
+		var enabled bool
+		if f != nil {
+			e, err := processFeature(f, ctx, visiting)
+			if err != nil {
+				return "", err
+			}
+			enabled = e.Enabled
+		}
+		if enabled != p.Enabled {
+			return p.ID(), nil
+		}
+	}
+	return "", nil
+}
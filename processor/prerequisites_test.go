@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/robzienert/lever/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func lookupFrom(features ...*model.Feature) model.FeatureLookup {
+	return func(namespace string, key string) (*model.Feature, error) {
+		for _, f := range features {
+			if f.Namespace == namespace && f.Key == key {
+				return f, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+func TestProcessFeature_Prerequisites(t *testing.T) {
+	backend := &model.Feature{Namespace: "api", Key: "backend", Gate: &model.Gate{Actors: []string{"one"}}}
+	legacy := &model.Feature{Key: "legacy", Gate: &model.Gate{Value: "false"}}
+	lookup := lookupFrom(backend, legacy)
+
+	tests := []struct {
+		prerequisites []model.Prerequisite
+		actors        string
+		expected      bool
+	}{
+		{[]model.Prerequisite{{Namespace: "api", Key: "backend", Enabled: true}}, "one", true},
+		{[]model.Prerequisite{{Namespace: "api", Key: "backend", Enabled: true}}, "two", false},
+		{[]model.Prerequisite{{Namespace: "api", Key: "backend", Enabled: true}, {Key: "legacy", Enabled: false}}, "one", true},
+		{[]model.Prerequisite{{Key: "legacy", Enabled: true}}, "one", false},
+		{[]model.Prerequisite{{Key: "missing", Enabled: true}}, "one", false},
+		{[]model.Prerequisite{{Key: "missing", Enabled: false}}, "one", true},
+	}
+	for i, tt := range tests {
+		f := &model.Feature{Key: "mobile", Gate: &model.Gate{Value: "true"}, Prerequisites: tt.prerequisites}
+		ctx := NewContext(tt.actors, "")
+		ctx.FeatureLookup = lookup
+		e, err := ProcessFeature(f, ctx)
+		assert.NoError(t, err, fmt.Sprintf("case %d", i+1))
+		assert.Equal(t, tt.expected, e.Enabled, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestProcessFeature_PrerequisiteErrors(t *testing.T) {
+	a := &model.Feature{Key: "a", Gate: &model.Gate{Value: "true"}, Prerequisites: []model.Prerequisite{{Key: "b", Enabled: true}}}
+	b := &model.Feature{Key: "b", Gate: &model.Gate{Value: "true"}, Prerequisites: []model.Prerequisite{{Key: "a", Enabled: true}}}
+
+	_, err := ProcessFeature(a, NewContext("", ""))
+	assert.Error(t, err, "no lookup")
+
+	ctx := NewContext("", "")
+	ctx.FeatureLookup = lookupFrom(a, b)
+	_, err = ProcessFeature(a, ctx)
+	assert.Error(t, err, "cycle")
+}
@@ -2,42 +2,191 @@ package processor
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/robzienert/lever/model"
 )
 
-type gateProcessor func(g *model.Gate, value string) bool
+// Match is the outcome of evaluating a single gate type, along with what it
+// matched on. Percentage gate types also report the actor's bucket when they do
+// not match.
+type Match struct {
+	Matched bool
+	Actor   string
+	Group   string
+	Segment string
+	CIDR    string
+	Bucket  *uint32
+}
+
+// GateProcessor evaluates a single gate type for a context.
+type GateProcessor func(g *model.Gate, ctx *Context) Match
+
+var gateProcessorLock sync.RWMutex
+
+var gateProcessorMap = map[string]GateProcessor{
+	model.BooleanGateType:           booleanProcessor,
+	model.PercentOfActorsGateType:   percentOfActorsProcessor,
+	model.PercentOfEveryoneGateType: percentOfEveryoneProcessor,
+	model.ActorsGateType:            actorsProcessor,
+	model.GroupsGateType:            groupsProcessor,
+	model.SegmentsGateType:          segmentsProcessor,
+	model.RulesGateType:             rulesProcessor,
+	model.ScheduleGateType:          scheduleProcessor,
+	model.PercentOfTimeGateType:     percentOfTimeProcessor,
+	model.CIDRsGateType:             cidrsProcessor,
+}
+
+// RegisterGateType adds a custom gate type along with the processor that
+// evaluates it. See model.GateType for how the type is configured.
+func RegisterGateType(t model.GateType, fn GateProcessor) error {
+	if fn == nil {
+		return fmt.Errorf("gate type is missing a processor: %s", t.Name)
+	}
+	gateProcessorLock.Lock()
+	defer gateProcessorLock.Unlock()
+	if err := model.RegisterGateType(t); err != nil {
+		return err
+	}
+	gateProcessorMap[t.Name] = fn
+	return nil
+}
 
-var gateProcessorMap = map[string]gateProcessor{
-	model.BooleanGateType:         booleanProcessor,
-	model.PercentOfActorsGateType: percentOfActorsProcessor,
-	model.ActorsGateType:          actorsProcessor,
-	model.GroupsGateType:          groupsProcessor,
-	model.PercentOfTimeGateType:   percentOfTimeProcessor,
+func gateProcessorFor(gateType string) GateProcessor {
+	gateProcessorLock.RLock()
+	defer gateProcessorLock.RUnlock()
+	return gateProcessorMap[gateType]
 }
 
-// ProcessGate will return the gate state of a feature given actors and groups.
-func ProcessGate(g *model.Gate, actors string, groups string) (bool, error) {
+// MatchReason means a gate type enabled the gate.
+//
+// NoMatchReason means no gate type enabled the gate. If a percentage gate type
+// bucketed an actor, its gate type and bucket are included.
+//
+// DeniedReason means a deny list disabled the gate.
+//
+// PrerequisiteReason means a prerequisite feature was not in its required
+// state.
+const (
+	MatchReason        = "match"
+	NoMatchReason      = "noMatch"
+	DeniedReason       = "denied"
+	PrerequisiteReason = "prerequisite"
+)
+
+// Result explains the outcome of evaluating a gate or feature.
+type Result struct {
+	Enabled      bool
+	Reason       string
+	GateType     string
+	Actor        string
+	Group        string
+	Segment      string
+	CIDR         string
+	Bucket       *uint32
+	Prerequisite string
+}
+
+func newResult(reason string, gateType string, m Match) *Result {
+	return &Result{
+		Enabled:  reason == MatchReason,
+		Reason:   reason,
+		GateType: gateType,
+		Actor:    m.Actor,
+		Group:    m.Group,
+		Segment:  m.Segment,
+		CIDR:     m.CIDR,
+		Bucket:   m.Bucket,
+	}
+}
+
+// ProcessGate will return the gate state of a feature given the evaluation
+// context, along with the reason for it. Denied actors and groups are disabled
+// regardless of the gate types.
+func ProcessGate(g *model.Gate, ctx *Context) (*Result, error) {
+	if m := denied(g, ctx); m.Matched {
+		return newResult(DeniedReason, "", m), nil
+	}
+	if names := g.SegmentNames(); len(names) > 0 {
+		if err := ctx.resolveSegments(names); err != nil {
+			return nil, err
+		}
+	}
+
+	var miss *Result
 	for _, gt := range g.Types() {
-		f := gateProcessorMap[gt]
+		f := gateProcessorFor(gt)
 		if f == nil {
-			return false, fmt.Errorf("could not load gate func for type: %s", gt)
+			return nil, fmt.Errorf("could not load gate func for type: %s", gt)
+		}
+		m := f(g, ctx)
+		if m.Matched {
+			return newResult(MatchReason, gt, m), nil
 		}
+		if miss == nil && m.Bucket != nil {
+			miss = newResult(NoMatchReason, gt, m)
+		}
+	}
+	if miss != nil {
+		return miss, nil
+	}
+	return &Result{Reason: NoMatchReason}, nil
+}
+
+// Evaluation is the result of evaluating a feature for a context. Value is the
+// raw value the context is served: the assigned variant's value or the
+// feature's value if enabled, its off value otherwise. It is empty if the
+// feature is disabled and has no off value.
+type Evaluation struct {
+	Result
+	Variant *model.Variant
+	Value   string
+}
 
-		var enabled bool
-		switch gt {
-		case model.ActorsGateType:
-			enabled = f(g, actors)
-		case model.PercentOfActorsGateType:
-			enabled = f(g, actors)
-		case model.GroupsGateType:
-			enabled = f(g, groups)
-		default:
-			enabled = f(g, "")
+// ProcessFeature will evaluate a feature's gate in the context's environment
+// and its prerequisites and, if the feature is enabled and multivariate, assign
+// the context a variant. It also chooses the value served to the context.
+func ProcessFeature(f *model.Feature, ctx *Context) (*Evaluation, error) {
+	return processFeature(f, ctx, make(map[string]bool))
+}
+
+func processFeature(f *model.Feature, ctx *Context, visiting map[string]bool) (*Evaluation, error) {
+	gate := f.EnvironmentGate(ctx.Environment)
+	seed := ctx.seed
+	ctx.seed = f.BucketSeed()
+	result, err := ProcessGate(gate, ctx)
+	ctx.seed = seed
+	if err != nil {
+		return nil, err
+	}
+	if result.Enabled && len(f.Prerequisites) > 0 {
+		visiting[f.ID()] = true
+		unmet, err := unmetPrerequisite(f.Prerequisites, ctx, visiting)
+		delete(visiting, f.ID())
+		if err != nil {
+			return nil, err
+		}
+		if unmet != "" {
+			result = &Result{Reason: PrerequisiteReason, Prerequisite: unmet}
 		}
-		if enabled {
-			return true, nil
+	}
+
+	e := &Evaluation{Result: *result}
+	if e.Enabled && len(f.Variants) > 0 {
+		seed := gate.Seed
+		if seed == "" {
+			seed = f.BucketSeed()
 		}
+		e.Variant = assignVariant(f.Variants, seed, ctx)
+	}
+
+	switch {
+	case e.Variant != nil:
+		e.Value = e.Variant.Value
+	case e.Enabled:
+		e.Value = f.Value
+	default:
+		e.Value = f.OffValue
 	}
-	return false, nil
+	return e, nil
 }
@@ -1,6 +1,8 @@
 package processor
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/robzienert/lever/model"
@@ -9,7 +11,170 @@ import (
 
 func TestProcessGate(t *testing.T) {
 	gate := &model.Gate{Value: "true"}
-	enabled, err := ProcessGate(gate, "", "")
+	result, err := ProcessGate(gate, NewContext("", ""))
 	assert.NoError(t, err)
-	assert.True(t, enabled)
+	assert.True(t, result.Enabled)
+}
+
+func regionProcessor(g *model.Gate, ctx *Context) Match {
+	var c struct {
+		Regions []string `json:"regions"`
+	}
+	if err := g.CustomConfig("testRegion", &c); err != nil {
+		return Match{}
+	}
+	for _, r := range c.Regions {
+		if ctx.Attributes["region"] == r {
+			return Match{Matched: true}
+		}
+	}
+	return Match{}
+}
+
+func TestRegisterGateType(t *testing.T) {
+	regionType := model.GateType{Name: "testRegion", Precedence: 150}
+	assert.Error(t, RegisterGateType(regionType, nil))
+	assert.NoError(t, RegisterGateType(regionType, regionProcessor))
+	assert.Error(t, RegisterGateType(regionType, regionProcessor))
+
+	gate := &model.Gate{
+		Groups: []string{"staff"},
+		Custom: map[string]json.RawMessage{"testRegion": json.RawMessage(`{"regions":["eu"]}`)},
+	}
+	ctx := NewContext("", "staff")
+	ctx.Attributes = map[string]string{"region": "eu"}
+	result, err := ProcessGate(gate, ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &Result{Enabled: true, Reason: MatchReason, GateType: "testRegion"}, result)
+
+	ctx.Attributes["region"] = "us"
+	result, err = ProcessGate(gate, ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, model.GroupsGateType, result.GateType)
+}
+
+func bucketRef(b uint32) *uint32 {
+	return &b
+}
+
+var processGateReasonTests = []struct {
+	gate     *model.Gate
+	ctx      *Context
+	expected *Result
+}{
+	{
+		&model.Gate{Value: "true"},
+		NewContext("", ""),
+		&Result{Enabled: true, Reason: MatchReason, GateType: model.BooleanGateType},
+	},
+	{
+		&model.Gate{Groups: []string{"staff"}, Actors: []string{"one"}},
+		NewContext("one", "staff"),
+		&Result{Enabled: true, Reason: MatchReason, GateType: model.GroupsGateType, Group: "staff"},
+	},
+	{
+		&model.Gate{PercentOfEveryone: 100, Seed: "fooFeature"},
+		NewContext("robzienert", ""),
+		&Result{Enabled: true, Reason: MatchReason, GateType: model.PercentOfEveryoneGateType, Actor: "robzienert", Bucket: bucketRef(77)},
+	},
+	{
+		&model.Gate{PercentOfEveryone: 5, Seed: "fooFeature"},
+		NewContext("robzienert", ""),
+		&Result{Reason: NoMatchReason, GateType: model.PercentOfEveryoneGateType, Actor: "robzienert", Bucket: bucketRef(77)},
+	},
+	{
+		&model.Gate{Actors: []string{"one"}},
+		NewContext("two", ""),
+		&Result{Reason: NoMatchReason},
+	},
+	{
+		&model.Gate{Value: "true", DenyGroups: []string{"enterprise"}},
+		NewContext("one", "enterprise"),
+		&Result{Reason: DeniedReason, Group: "enterprise"},
+	},
+}
+
+func TestProcessGate_Reasons(t *testing.T) {
+	for i, tt := range processGateReasonTests {
+		actual, err := ProcessGate(tt.gate, tt.ctx)
+		assert.NoError(t, err, fmt.Sprintf("case %d", i+1))
+		assert.Equal(t, tt.expected, actual, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestProcessFeature(t *testing.T) {
+	f := &model.Feature{
+		Key:      "fooFeature",
+		Gate:     &model.Gate{Actors: []string{"one"}},
+		Variants: abVariants,
+	}
+
+	e, err := ProcessFeature(f, NewContext("one", ""))
+	assert.NoError(t, err)
+	assert.True(t, e.Enabled)
+	assert.NotNil(t, e.Variant)
+
+	e, err = ProcessFeature(f, NewContext("two", ""))
+	assert.NoError(t, err)
+	assert.False(t, e.Enabled)
+	assert.Nil(t, e.Variant)
+}
+
+var processFeatureValueTests = []struct {
+	feature  *model.Feature
+	actors   string
+	expected string
+}{
+	{&model.Feature{Value: "on", OffValue: "off", Gate: &model.Gate{Actors: []string{"one"}}}, "one", "on"},
+	{&model.Feature{Value: "on", OffValue: "off", Gate: &model.Gate{Actors: []string{"one"}}}, "two", "off"},
+	{&model.Feature{Value: "on", Gate: &model.Gate{Actors: []string{"one"}}}, "two", ""},
+	{&model.Feature{Value: "on", OffValue: "off", Gate: &model.Gate{Actors: []string{"one"}}, Variants: []model.Variant{{Name: "a", Weight: 1, Value: "a"}}}, "one", "a"},
+	{&model.Feature{Value: "on", OffValue: "off", Gate: &model.Gate{Actors: []string{"one"}}, Variants: []model.Variant{{Name: "a", Weight: 1, Value: "a"}}}, "two", "off"},
+}
+
+func TestProcessFeature_Value(t *testing.T) {
+	for i, tt := range processFeatureValueTests {
+		e, err := ProcessFeature(tt.feature, NewContext(tt.actors, ""))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, e.Value, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestProcessFeature_PrerequisiteReason(t *testing.T) {
+	f := &model.Feature{
+		Key:           "fooFeature",
+		Gate:          &model.Gate{Value: "true"},
+		Prerequisites: []model.Prerequisite{{Namespace: "api", Key: "backend", Enabled: true}},
+	}
+	ctx := NewContext("one", "")
+	ctx.FeatureLookup = lookupFrom()
+
+	e, err := ProcessFeature(f, ctx)
+	assert.NoError(t, err)
+	assert.False(t, e.Enabled)
+	assert.Equal(t, PrerequisiteReason, e.Reason)
+	assert.Equal(t, "api/backend", e.Prerequisite)
+}
+
+func TestProcessFeature_Environment(t *testing.T) {
+	backend := &model.Feature{
+		Namespace:    "api",
+		Key:          "backend",
+		Gate:         &model.Gate{Value: "false"},
+		Environments: map[string]*model.Gate{"staging": {Value: "true"}},
+	}
+	f := &model.Feature{
+		Key:           "fooFeature",
+		Gate:          &model.Gate{Value: "true"},
+		Prerequisites: []model.Prerequisite{{Namespace: "api", Key: "backend", Enabled: true}},
+	}
+
+	for env, enabled := range map[string]bool{"": false, "staging": true} {
+		ctx := NewContext("one", "")
+		ctx.Environment = env
+		ctx.FeatureLookup = lookupFrom(backend)
+		e, err := ProcessFeature(f, ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, enabled, e.Enabled, env)
+	}
 }
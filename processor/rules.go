@@ -0,0 +1,89 @@
+package processor
+
+import (
+	"strconv"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/shared/semverutil"
+	"github.com/robzienert/lever/shared/strutil"
+)
+
+// rulesProcessor enables the gate only if every rule matches. An attribute
+// missing from the context never matches, regardless of the operator.
+func rulesProcessor(g *model.Gate, ctx *Context) Match {
+	return Match{Matched: matchRules(g.Rules, ctx)}
+}
+
+func matchRules(rules []model.Rule, ctx *Context) bool {
+	if len(rules) == 0 {
+		return false
+	}
+	for _, r := range rules {
+		value, ok := ctx.Attributes[r.Attribute]
+		if !ok || !matchRule(r, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchRule(r model.Rule, value string) bool {
+	if len(r.Values) == 0 {
+		return false
+	}
+	switch r.Operator {
+	case model.EqualsOperator:
+		return value == r.Values[0]
+	case model.NotEqualsOperator:
+		return value != r.Values[0]
+	case model.InOperator:
+		return strutil.StringInSlice(value, r.Values)
+	case model.NotInOperator:
+		return !strutil.StringInSlice(value, r.Values)
+	case model.RegexOperator:
+		re, err := r.Regexp()
+		return err == nil && re.MatchString(value)
+	case model.GreaterThanOperator:
+		return compareNumeric(value, r.Values[0], func(c int) bool { return c > 0 })
+	case model.GreaterThanOrEqualOperator:
+		return compareNumeric(value, r.Values[0], func(c int) bool { return c >= 0 })
+	case model.LessThanOperator:
+		return compareNumeric(value, r.Values[0], func(c int) bool { return c < 0 })
+	case model.LessThanOrEqualOperator:
+		return compareNumeric(value, r.Values[0], func(c int) bool { return c <= 0 })
+	case model.SemverEqualsOperator:
+		return compareSemver(value, r.Values[0], func(c int) bool { return c == 0 })
+	case model.SemverGreaterThanOperator:
+		return compareSemver(value, r.Values[0], func(c int) bool { return c > 0 })
+	case model.SemverGreaterThanOrEqualOperator:
+		return compareSemver(value, r.Values[0], func(c int) bool { return c >= 0 })
+	case model.SemverLessThanOperator:
+		return compareSemver(value, r.Values[0], func(c int) bool { return c < 0 })
+	case model.SemverLessThanOrEqualOperator:
+		return compareSemver(value, r.Values[0], func(c int) bool { return c <= 0 })
+	}
+	return false
+}
+
+func compareNumeric(a string, b string, ok func(int) bool) bool {
+	fa, err := strconv.ParseFloat(a, 64)
+	if err != nil {
+		return false
+	}
+	fb, err := strconv.ParseFloat(b, 64)
+	if err != nil {
+		return false
+	}
+	switch {
+	case fa < fb:
+		return ok(-1)
+	case fa > fb:
+		return ok(1)
+	}
+	return ok(0)
+}
+
+func compareSemver(a string, b string, ok func(int) bool) bool {
+	c, err := semverutil.Compare(a, b)
+	return err == nil && ok(c)
+}
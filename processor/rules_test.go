@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/robzienert/lever/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var rulesTests = []struct {
+	rules      []model.Rule
+	attributes map[string]string
+	expected   bool
+}{
+	{
+		nil,
+		map[string]string{"country": "DE"},
+		false,
+	},
+	{
+		[]model.Rule{{Attribute: "country", Operator: model.EqualsOperator, Values: []string{"DE"}}},
+		map[string]string{"country": "DE"},
+		true,
+	},
+	{
+		[]model.Rule{{Attribute: "country", Operator: model.EqualsOperator, Values: []string{"DE"}}},
+		map[string]string{},
+		false,
+	},
+	{
+		[]model.Rule{{Attribute: "country", Operator: model.NotInOperator, Values: []string{"CN", "RU"}}},
+		map[string]string{},
+		false,
+	},
+	{
+		[]model.Rule{{Attribute: "country", Operator: model.InOperator, Values: []string{"DE", "FR"}}},
+		map[string]string{"country": "FR"},
+		true,
+	},
+	{
+		[]model.Rule{{Attribute: "country", Operator: model.NotInOperator, Values: []string{"DE", "FR"}}},
+		map[string]string{"country": "FR"},
+		false,
+	},
+	{
+		[]model.Rule{{Attribute: "email", Operator: model.RegexOperator, Values: []string{"@example\\.com$"}}},
+		map[string]string{"email": "rob@example.com"},
+		true,
+	},
+	{
+		[]model.Rule{{Attribute: "age", Operator: model.GreaterThanOrEqualOperator, Values: []string{"18"}}},
+		map[string]string{"age": "18"},
+		true,
+	},
+	{
+		[]model.Rule{{Attribute: "age", Operator: model.LessThanOperator, Values: []string{"18"}}},
+		map[string]string{"age": "eighteen"},
+		false,
+	},
+	{
+		[]model.Rule{{Attribute: "appVersion", Operator: model.SemverGreaterThanOrEqualOperator, Values: []string{"5.2.0"}}},
+		map[string]string{"appVersion": "5.10.1"},
+		true,
+	},
+	{
+		[]model.Rule{{Attribute: "appVersion", Operator: model.SemverGreaterThanOperator, Values: []string{"5.2.1"}}},
+		map[string]string{"appVersion": "5.2.1-beta.1"},
+		false,
+	},
+	{
+		[]model.Rule{
+			{Attribute: "country", Operator: model.EqualsOperator, Values: []string{"DE"}},
+			{Attribute: "plan", Operator: model.InOperator, Values: []string{"pro", "enterprise"}},
+		},
+		map[string]string{"country": "DE", "plan": "free"},
+		false,
+	},
+	{
+		[]model.Rule{
+			{Attribute: "country", Operator: model.EqualsOperator, Values: []string{"DE"}},
+			{Attribute: "plan", Operator: model.InOperator, Values: []string{"pro", "enterprise"}},
+		},
+		map[string]string{"country": "DE", "plan": "pro"},
+		true,
+	},
+}
+
+func TestRulesProcessor(t *testing.T) {
+	for i, tt := range rulesTests {
+		actual := rulesProcessor(&model.Gate{Rules: tt.rules}, &Context{Attributes: tt.attributes}).Matched
+		if tt.expected {
+			assert.True(t, actual, fmt.Sprintf("case %d", i+1))
+		} else {
+			assert.False(t, actual, fmt.Sprintf("case %d", i+1))
+		}
+	}
+}
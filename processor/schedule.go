@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"time"
+
+	"github.com/robzienert/lever/model"
+)
+
+func scheduleProcessor(g *model.Gate, ctx *Context) Match {
+	return Match{Matched: scheduleOpen(g.Schedule, ctx.now())}
+}
+
+func scheduleOpen(s *model.Schedule, now time.Time) bool {
+	if s == nil {
+		return false
+	}
+
+	if s.Start != nil && now.Before(*s.Start) {
+		return false
+	}
+	if s.End != nil && !now.Before(*s.End) {
+		return false
+	}
+	if len(s.Windows) == 0 {
+		return true
+	}
+	for _, w := range s.Windows {
+		if windowOpen(w, now) {
+			return true
+		}
+	}
+	return false
+}
+
+func windowOpen(w model.Window, now time.Time) bool {
+	start, end, err := w.Minutes()
+	if err != nil {
+		return false
+	}
+	loc, err := w.Location()
+	if err != nil {
+		return false
+	}
+	days, err := w.Weekdays()
+	if err != nil {
+		return false
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return minute >= start && minute < end && onDay(days, local.Weekday())
+	}
+
+	// Windows spanning midnight belong to the day they open on.
+	if minute >= start {
+		return onDay(days, local.Weekday())
+	}
+	if minute < end {
+		return onDay(days, (local.Weekday()+6)%7)
+	}
+	return false
+}
+
+func onDay(days []time.Weekday, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
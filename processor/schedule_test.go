@@ -0,0 +1,91 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robzienert/lever/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func timeRef(value string) *time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return &t
+}
+
+var scheduleTests = []struct {
+	schedule *model.Schedule
+	now      string
+	expected bool
+}{
+	{
+		nil,
+		"2026-10-19T10:00:00Z",
+		false,
+	},
+	{
+		&model.Schedule{Start: timeRef("2026-11-01T00:00:00Z")},
+		"2026-10-31T23:59:59Z",
+		false,
+	},
+	{
+		&model.Schedule{Start: timeRef("2026-11-01T00:00:00Z")},
+		"2026-11-01T00:00:00Z",
+		true,
+	},
+	{
+		&model.Schedule{Start: timeRef("2026-11-01T00:00:00Z"), End: timeRef("2026-11-30T00:00:00Z")},
+		"2026-11-30T00:00:00Z",
+		false,
+	},
+	{
+		// Monday, 10:00 in Berlin.
+		&model.Schedule{Windows: []model.Window{{Days: []string{"Monday", "Tuesday"}, Start: "09:00", End: "17:00", TimeZone: "Europe/Berlin"}}},
+		"2026-10-19T08:00:00Z",
+		true,
+	},
+	{
+		// Monday, 18:00 in Berlin.
+		&model.Schedule{Windows: []model.Window{{Days: []string{"monday"}, Start: "09:00", End: "17:00", TimeZone: "Europe/Berlin"}}},
+		"2026-10-19T16:00:00Z",
+		false,
+	},
+	{
+		// Sunday, 10:00 UTC.
+		&model.Schedule{Windows: []model.Window{{Days: []string{"monday"}, Start: "09:00", End: "17:00"}}},
+		"2026-10-18T10:00:00Z",
+		false,
+	},
+	{
+		// Tuesday, 01:00 UTC, in a window opening Monday night.
+		&model.Schedule{Windows: []model.Window{{Days: []string{"monday"}, Start: "22:00", End: "02:00"}}},
+		"2026-10-20T01:00:00Z",
+		true,
+	},
+	{
+		// Monday, 01:00 UTC, in a window opening Sunday night.
+		&model.Schedule{Windows: []model.Window{{Days: []string{"monday"}, Start: "22:00", End: "02:00"}}},
+		"2026-10-19T01:00:00Z",
+		false,
+	},
+	{
+		// Within the windows, but after the end of the schedule.
+		&model.Schedule{End: timeRef("2026-10-01T00:00:00Z"), Windows: []model.Window{{Start: "00:00", End: "23:59"}}},
+		"2026-10-19T10:00:00Z",
+		false,
+	},
+}
+
+func TestScheduleProcessor(t *testing.T) {
+	for i, tt := range scheduleTests {
+		now := timeRef(tt.now)
+		ctx := &Context{Clock: func() time.Time { return *now }}
+		actual := scheduleProcessor(&model.Gate{Schedule: tt.schedule}, ctx).Matched
+		if tt.expected {
+			assert.True(t, actual, fmt.Sprintf("case %d", i+1))
+		} else {
+			assert.False(t, actual, fmt.Sprintf("case %d", i+1))
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/shared/strutil"
+)
+
+// segmentsProcessor enables the gate if the context is in any of its segments.
+// Segments, including those of expression gates, are resolved by ProcessGate
+// before the gate types are evaluated.
+func segmentsProcessor(g *model.Gate, ctx *Context) Match {
+	for _, name := range g.Segments {
+		if s := ctx.segments[name]; s != nil && inSegment(s, ctx) {
+			return Match{Matched: true, Segment: name}
+		}
+	}
+	return Match{}
+}
+
+func inSegment(s *model.Segment, ctx *Context) bool {
+	for _, actor := range ctx.Actors {
+		if strutil.StringInSlice(actor, s.Actors) {
+			return true
+		}
+	}
+	for _, group := range ctx.Groups {
+		if strutil.StringInSlice(group, s.Groups) {
+			return true
+		}
+	}
+	return matchRules(s.Rules, ctx)
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/robzienert/lever/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSegments = map[string]*model.Segment{
+	"beta": {Name: "beta", Actors: []string{"one", "two"}, Groups: []string{"staff"}},
+	"germany": {Name: "germany", Rules: []model.Rule{
+		{Attribute: "country", Operator: model.EqualsOperator, Values: []string{"DE"}},
+	}},
+}
+
+var segmentsTests = []struct {
+	segments   []string
+	actors     string
+	groups     string
+	attributes map[string]string
+	expected   bool
+}{
+	{[]string{"beta"}, "one", "", nil, true},
+	{[]string{"beta"}, "three", "", nil, false},
+	{[]string{"beta"}, "three", "staff", nil, true},
+	{[]string{"beta", "germany"}, "three", "", map[string]string{"country": "DE"}, true},
+	{[]string{"germany"}, "one", "", map[string]string{"country": "FR"}, false},
+	{[]string{"missing"}, "one", "", nil, false},
+}
+
+func TestSegmentsProcessor(t *testing.T) {
+	lookups := 0
+	for i, tt := range segmentsTests {
+		ctx := NewContext(tt.actors, tt.groups)
+		ctx.Attributes = tt.attributes
+		ctx.SegmentLookup = func(name string) (*model.Segment, error) {
+			lookups++
+			return testSegments[name], nil
+		}
+
+		actual, err := ProcessGate(&model.Gate{Segments: tt.segments}, ctx)
+		assert.NoError(t, err, fmt.Sprintf("case %d", i+1))
+		assert.Equal(t, tt.expected, actual.Enabled, fmt.Sprintf("case %d", i+1))
+
+		// Segments are only looked up once per context.
+		before := lookups
+		ProcessGate(&model.Gate{Segments: tt.segments}, ctx)
+		assert.Equal(t, before, lookups, fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestSegmentsProcessor_LookupErrors(t *testing.T) {
+	_, err := ProcessGate(&model.Gate{Segments: []string{"beta"}}, NewContext("one", ""))
+	assert.Error(t, err, "no lookup")
+
+	ctx := NewContext("one", "")
+	ctx.SegmentLookup = func(name string) (*model.Segment, error) {
+		return nil, errors.New("oh no")
+	}
+	_, err = ProcessGate(&model.Gate{Segments: []string{"beta"}}, ctx)
+	assert.Error(t, err)
+}
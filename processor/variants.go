@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"github.com/robzienert/lever/model"
+	"github.com/spaolacci/murmur3"
+)
+
+// assignVariant deterministically picks a variant for the first actor in the
+// context. Actors are hashed as "<seed>:variants:<actor>" so that assignments
+// are independent of the actor's percentage bucket. Without an actor, the first
+// variant is assigned.
+func assignVariant(variants []model.Variant, seed string, ctx *Context) *model.Variant {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return nil
+	}
+	if len(ctx.Actors) == 0 {
+		return &variants[0]
+	}
+
+	point := int(murmur3.Sum32([]byte(seed+":variants:"+ctx.Actors[0])) % uint32(total))
+	for i := range variants {
+		if point < variants[i].Weight {
+			return &variants[i]
+		}
+		point -= variants[i].Weight
+	}
+	return nil
+}
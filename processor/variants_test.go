@@ -0,0 +1,42 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/robzienert/lever/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var abVariants = []model.Variant{
+	{Name: "control", Weight: 50, Value: "blue"},
+	{Name: "treatment", Weight: 50, Value: "green"},
+}
+
+func TestAssignVariant_Deterministic(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		ctx := NewContext(fmt.Sprintf("actor-%d", i), "")
+		assert.Equal(t, assignVariant(abVariants, "fooFeature", ctx), assignVariant(abVariants, "fooFeature", ctx))
+	}
+}
+
+func TestAssignVariant_Weights(t *testing.T) {
+	variants := []model.Variant{
+		{Name: "a", Weight: 1},
+		{Name: "b", Weight: 0},
+		{Name: "c", Weight: 3},
+	}
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		v := assignVariant(variants, "fooFeature", NewContext(fmt.Sprintf("actor-%d", i), ""))
+		counts[v.Name]++
+	}
+	assert.InDelta(t, 2500, counts["a"], 250)
+	assert.Zero(t, counts["b"])
+	assert.InDelta(t, 7500, counts["c"], 250)
+}
+
+func TestAssignVariant_NoActor(t *testing.T) {
+	assert.Equal(t, "control", assignVariant(abVariants, "fooFeature", NewContext("", "")).Name)
+	assert.Nil(t, assignVariant([]model.Variant{{Name: "a"}}, "fooFeature", NewContext("one", "")))
+}
@@ -0,0 +1,109 @@
+package rollout
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+	"github.com/Sirupsen/logrus"
+	"github.com/robzienert/lever/metrics"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+	"golang.org/x/net/context"
+)
+
+// Scheduler periodically advances the rollout plans of all features whose next
+// step is due.
+//
+// Every server runs its own scheduler. Steps are saved at the revision they were
+// read at, so when instances advance the same step at once, only one of them
+// saves it and its breadcrumb. The others fail with store.ErrConflict, which is
+// logged, and check the feature again on the next tick, when the step is no
+// longer due. Rollouts that conflict with other changes are retried the same
+// way.
+type Scheduler struct {
+	ctx      context.Context
+	interval time.Duration
+	clock    func() time.Time
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// NewScheduler creates a scheduler that checks the features in the store for
+// due rollout steps every interval.
+func NewScheduler(s store.Store, statsd *statsd.Client, interval time.Duration) *Scheduler {
+	ctx := context.WithValue(context.Background(), store.Key, s)
+	if statsd != nil {
+		ctx = context.WithValue(ctx, metrics.Key, statsd)
+	}
+	return &Scheduler{
+		ctx:      ctx,
+		interval: interval,
+		clock:    time.Now,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler in the background until it is closed.
+func (s *Scheduler) Start() {
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Tick()
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the scheduler and waits for a running tick to finish.
+func (s *Scheduler) Close() {
+	close(s.done)
+	<-s.stopped
+}
+
+// Tick advances every rollout that is due by a single step. Rollouts that are
+// overdue by several intervals do not skip steps.
+func (s *Scheduler) Tick() {
+	features, err := store.GetAllFeatures(s.ctx)
+	if err != nil {
+		logrus.WithField("err", err).Error("Could not list features to advance rollouts")
+		return
+	}
+
+	now := s.clock().UTC()
+	for _, f := range features {
+		if f.Rollout == nil || !f.Rollout.Due(now) {
+			continue
+		}
+
+		next := f.Copy()
+		next.Rollout.Advance(now)
+		next.Gate.ActorPercent = next.Rollout.Percent()
+		next.LastUpdated = now
+		if err := store.UpsertFeature(s.ctx, next); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"err": err,
+				"key": f.Key,
+				"ns":  f.Namespace,
+			}).Error("Could not advance rollout")
+			continue
+		}
+
+		fields := f.Diff(next)
+		fields["key"] = f.Key
+		fields["ns"] = f.Namespace
+		breadcrumb := model.NewBreadcrumb("advance rollout", model.AutomatedActor).WithFields(fields)
+		if err := store.SaveBreadcrumb(s.ctx, breadcrumb); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"err": err,
+				"b":   *breadcrumb,
+			}).Error("Could not save breadcrumb")
+		}
+	}
+}
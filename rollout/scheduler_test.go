@@ -0,0 +1,70 @@
+package rollout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_Tick(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Minute)
+	later := now.Add(time.Hour)
+
+	memStore := memory.Load()
+	memStore.Features().Upsert(&model.Feature{
+		Namespace: "mobile",
+		Key:       "due",
+		Gate:      &model.Gate{ActorPercent: 1},
+		Rollout:   &model.Rollout{Steps: []int{1, 5, 100}, Interval: "24h", State: model.RolloutRunningState, NextStepAt: &due},
+	})
+	memStore.Features().Upsert(&model.Feature{
+		Key:     "later",
+		Gate:    &model.Gate{ActorPercent: 1},
+		Rollout: &model.Rollout{Steps: []int{1, 5, 100}, Interval: "24h", State: model.RolloutRunningState, NextStepAt: &later},
+	})
+	memStore.Features().Upsert(&model.Feature{
+		Key:     "paused",
+		Gate:    &model.Gate{ActorPercent: 1},
+		Rollout: &model.Rollout{Steps: []int{1, 5, 100}, Interval: "24h", State: model.RolloutPausedState},
+	})
+
+	s := NewScheduler(memStore, nil, time.Minute)
+	s.clock = func() time.Time { return now }
+	s.Tick()
+
+	f, _ := memStore.Features().GetByNamespace("mobile", "due")
+	assert.Equal(t, 5, f.Gate.ActorPercent)
+	assert.Equal(t, 1, f.Rollout.Step)
+	assert.Equal(t, now.Add(24*time.Hour), *f.Rollout.NextStepAt)
+
+	f, _ = memStore.Features().Get("later")
+	assert.Equal(t, 1, f.Gate.ActorPercent)
+	f, _ = memStore.Features().Get("paused")
+	assert.Equal(t, 1, f.Gate.ActorPercent)
+
+	breadcrumbs, _ := memStore.Breadcrumbs().GetList()
+	if assert.Len(t, breadcrumbs, 1) {
+		assert.Equal(t, "advance rollout", breadcrumbs[0].Action)
+		assert.Equal(t, model.AutomatedActor, breadcrumbs[0].Actor)
+		assert.Equal(t, "1 -> 5", breadcrumbs[0].Fields["gate_actor_percent"])
+		assert.Equal(t, "due", breadcrumbs[0].Fields["key"])
+	}
+
+	// The last step completes the rollout.
+	s.clock = func() time.Time { return now.Add(24 * time.Hour) }
+	s.Tick()
+	f, _ = memStore.Features().GetByNamespace("mobile", "due")
+	assert.Equal(t, 100, f.Gate.ActorPercent)
+	assert.Equal(t, model.RolloutCompletedState, f.Rollout.State)
+}
+
+func TestScheduler_StartClose(t *testing.T) {
+	s := NewScheduler(memory.Load(), nil, time.Millisecond)
+	s.Start()
+	time.Sleep(5 * time.Millisecond)
+	s.Close()
+}
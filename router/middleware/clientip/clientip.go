@@ -0,0 +1,98 @@
+package clientip
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+// Key is the net.Context key for the caller's IP.
+const Key = "clientIP"
+
+// Set determines the caller's IP and sets it into the net.Context.
+//
+// The IP is the request's remote address, unless it is one of the trusted
+// proxies. In that case, the X-Forwarded-For header is walked from the last hop
+// back, and the first hop that is not a trusted proxy is the caller. Without
+// trusted proxies, X-Forwarded-For is ignored, as any caller can set it.
+func Set(trustedProxies []*net.IPNet) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ip := FromRequest(c.Request, trustedProxies); ip != nil {
+			c.Set(Key, ip)
+		}
+		c.Next()
+	}
+}
+
+// FromContext returns the caller's IP, or nil if it is not known.
+func FromContext(c context.Context) net.IP {
+	if ip, ok := c.Value(Key).(net.IP); ok {
+		return ip
+	}
+	return nil
+}
+
+// FromRequest returns the caller's IP, trusting X-Forwarded-For only as far as
+// the given proxies.
+func FromRequest(r *http.Request, trustedProxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !trusted(ip, trustedProxies) {
+		return ip
+	}
+
+	var hops []string
+	for _, header := range r.Header[http.CanonicalHeaderKey("X-Forwarded-For")] {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			break
+		}
+		ip = hop
+		if !trusted(hop, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+// ParseNetworks parses a list of CIDR ranges, such as the trusted proxies
+// config. Single IPs are parsed as a range of one.
+func ParseNetworks(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		}
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, n)
+	}
+	return networks, nil
+}
+
+func trusted(ip net.IP, trustedProxies []*net.IPNet) bool {
+	for _, n := range trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fromRequestTests = []struct {
+	remoteAddr     string
+	forwardedFor   []string
+	trustedProxies []string
+	expected       string
+}{
+	{"203.0.113.7:4711", nil, nil, "203.0.113.7"},
+	{"203.0.113.7:4711", []string{"10.1.2.3"}, nil, "203.0.113.7"},
+	{"203.0.113.7:4711", []string{"10.1.2.3"}, []string{"192.168.0.0/16"}, "203.0.113.7"},
+	{"192.168.0.1:4711", []string{"10.1.2.3"}, []string{"192.168.0.0/16"}, "10.1.2.3"},
+	{"192.168.0.1:4711", []string{"6.6.6.6, 10.1.2.3, 192.168.0.2"}, []string{"192.168.0.0/16"}, "10.1.2.3"},
+	{"192.168.0.1:4711", []string{"6.6.6.6", "10.1.2.3"}, []string{"192.168.0.0/16", "10.0.0.0/8"}, "6.6.6.6"},
+	{"192.168.0.1:4711", []string{"192.168.0.2"}, []string{"192.168.0.0/16"}, "192.168.0.2"},
+	{"192.168.0.1:4711", []string{"garbage, 10.1.2.3"}, []string{"192.168.0.0/16", "10.0.0.0/8"}, "10.1.2.3"},
+	{"192.168.0.1:4711", nil, []string{"192.168.0.1"}, "192.168.0.1"},
+	{"[2001:db8::1]:4711", []string{"2001:db8::2"}, []string{"2001:db8::1"}, "2001:db8::2"},
+}
+
+func TestFromRequest(t *testing.T) {
+	for i, tt := range fromRequestTests {
+		trustedProxies, err := ParseNetworks(tt.trustedProxies)
+		assert.NoError(t, err)
+		r := &http.Request{RemoteAddr: tt.remoteAddr, Header: http.Header{"X-Forwarded-For": tt.forwardedFor}}
+		assert.Equal(t, net.ParseIP(tt.expected).String(), FromRequest(r, trustedProxies).String(), fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks([]string{"10.0.0.0/8", "192.168.0.1", "2001:db8::1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", networks[0].String())
+	assert.Equal(t, "192.168.0.1/32", networks[1].String())
+	assert.Equal(t, "2001:db8::1/128", networks[2].String())
+
+	_, err = ParseNetworks([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+}
@@ -5,8 +5,10 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/gin-gonic/gin"
 	"github.com/robzienert/http-healthcheck"
+	"github.com/robzienert/lever/exposure"
 	"github.com/robzienert/lever/metrics"
 	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/usage"
 	"github.com/satori/go.uuid"
 )
 
@@ -35,6 +37,23 @@ func SetStore(s store.Store) gin.HandlerFunc {
 	}
 }
 
+// SetUsageTracker will set the feature usage tracker into the net.Context.
+func SetUsageTracker(t *usage.Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(usage.Key, t)
+		c.Next()
+	}
+}
+
+// SetExposureExporter will set the exposure event exporter into the
+// net.Context.
+func SetExposureExporter(e *exposure.Exporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(exposure.Key, e)
+		c.Next()
+	}
+}
+
 // SetRequestUUID will search for an X-ST-CORRELATION header and set a
 // request-level correlation ID into the net.Context. If no header is found, a
 // new UUID will be generated.
@@ -1,15 +1,24 @@
 package session
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/gin-gonic/gin"
 	"github.com/robzienert/gin-middleware/oauth"
+	"github.com/robzienert/lever/api"
 	"github.com/robzienert/lever/shared/strutil"
+	"golang.org/x/net/context"
 )
 
+// ServiceKey is the net.Context key that marks requests authorized with a
+// service-scoped token.
+const ServiceKey = "service"
+
 // User is a convenience net.Context function for retrieving the authorized
 // OAuth user.
 func User(c *gin.Context) *oauth.User {
@@ -34,13 +43,33 @@ func AuditActor(c *gin.Context) string {
 	return token.ClientID
 }
 
+// IsService is a convenience net.Context function for determining if the
+// request was authorized by AuthFeatureState with a service-scoped token.
+func IsService(c context.Context) bool {
+	service, _ := c.Value(ServiceKey).(bool)
+	return service
+}
+
+// ClientID is a convenience net.Context function for retrieving the OAuth client
+// of the request, or an empty string without a token.
+func ClientID(c *gin.Context) string {
+	token := oauth.Token(c)
+	if token == nil {
+		return ""
+	}
+	return token.ClientID
+}
+
 // AuthFeatureState contains the logic to determine if a request to process
 // feature state should be allowed. If the request is allowed, this function
 // will return a nil error.
 //
-// 1. Service-scoped requests are allowed-all.
+// 1. Service-scoped requests are allowed-all, and are marked so that the IP
+// they pass explicitly is trusted.
 // 2. Mobile-scoped requests must have a user object and if "Actors" have been
-// passed in the request, the user's username may be the only actor value.
+// passed in the request, the user's username may be the only actor value. The
+// same applies to the actor of an evaluation context in the request body. Any
+// IP they pass is ignored in favor of the caller's IP.
 func AuthFeatureState() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := oauth.Token(c)
@@ -50,6 +79,7 @@ func AuthFeatureState() gin.HandlerFunc {
 			return
 		}
 		if strutil.StringInSlice("service", token.Scopes) {
+			c.Set(ServiceKey, true)
 			c.Next()
 			return
 		}
@@ -60,7 +90,7 @@ func AuthFeatureState() gin.HandlerFunc {
 			return
 		}
 		actors := c.Query("actors")
-		if actors == "" || user.Username == actors {
+		if (actors == "" || user.Username == actors) && bodyActorAllowed(c, user.Username) {
 			c.Next()
 			return
 		}
@@ -68,3 +98,26 @@ func AuthFeatureState() gin.HandlerFunc {
 		c.AbortWithStatus(http.StatusForbidden)
 	}
 }
+
+// bodyActorAllowed peeks at the evaluation context in the request body, if one
+// was sent, and checks its actor against the given username. The body is
+// restored so that it can still be bound by the handler.
+func bodyActorAllowed(c *gin.Context, username string) bool {
+	if c.Request.Body == nil {
+		return true
+	}
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		return false
+	}
+	c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var in struct {
+		Context api.EvaluationContext `json:"context"`
+	}
+	if err := json.Unmarshal(body, &in); err != nil {
+		// Malformed bodies are rejected by the handler when bound.
+		return true
+	}
+	return in.Context.Actor == "" || in.Context.Actor == username
+}
@@ -51,8 +51,23 @@ func Load(oauthValidator oauth.TokenValidator, middleware ...gin.HandlerFunc) ht
 			features.PUT("/:key", mustService, controllers.PutFeature)
 			features.DELETE("/:key", mustService, controllers.DeleteFeature)
 			features.GET("/:key/state", mustConsumer, authFeatureState, controllers.GetFeatureState)
+			features.POST("/:key/state", mustConsumer, authFeatureState, controllers.PostFeatureState)
+			features.POST("/:key/rollout/pause", mustService, controllers.PauseRollout)
+			features.POST("/:key/rollout/resume", mustService, controllers.ResumeRollout)
+			features.POST("/:key/rollout/abort", mustService, controllers.AbortRollout)
+			features.POST("/:key/promote", mustService, controllers.PromoteFeature)
 		}
+		segments := api.Group("/segments")
+		{
+			segments.GET("", mustConsumer, controllers.GetAllSegments)
+			segments.GET("/:name", mustConsumer, controllers.GetSegment)
+			segments.PUT("/:name", mustService, controllers.PutSegment)
+			segments.DELETE("/:name", mustService, controllers.DeleteSegment)
+		}
+		api.POST("/state", mustConsumer, authFeatureState, controllers.PostBatchEvaluation)
 		api.GET("/audit", mustService, controllers.GetAuditIndex)
+		api.GET("/reports/stale", mustService, controllers.GetStaleReport)
+		api.GET("/usage", mustService, controllers.GetUsage)
 	}
 
 	e.GET("/status", controllers.GetHealthStatus)
@@ -29,12 +29,24 @@ func (suite *RouterTestSuite) TestLoad_Routes() {
 
 	routes := router.Routes()
 	assertRouteExists(suite.T(), routes, "GET", "/api/audit", controllers.GetAuditIndex)
+	assertRouteExists(suite.T(), routes, "GET", "/api/reports/stale", controllers.GetStaleReport)
+	assertRouteExists(suite.T(), routes, "GET", "/api/usage", controllers.GetUsage)
 	assertRouteExists(suite.T(), routes, "GET", "/api/features", controllers.GetAllFeatures)
 	assertRouteExists(suite.T(), routes, "POST", "/api/features", controllers.PostBatchFeatureState)
 	assertRouteExists(suite.T(), routes, "GET", "/api/features/:key", controllers.GetFeature)
 	assertRouteExists(suite.T(), routes, "PUT", "/api/features/:key", controllers.PutFeature)
 	assertRouteExists(suite.T(), routes, "DELETE", "/api/features/:key", controllers.DeleteFeature)
 	assertRouteExists(suite.T(), routes, "GET", "/api/features/:key/state", controllers.GetFeatureState)
+	assertRouteExists(suite.T(), routes, "POST", "/api/features/:key/state", controllers.PostFeatureState)
+	assertRouteExists(suite.T(), routes, "POST", "/api/features/:key/rollout/pause", controllers.PauseRollout)
+	assertRouteExists(suite.T(), routes, "POST", "/api/features/:key/rollout/resume", controllers.ResumeRollout)
+	assertRouteExists(suite.T(), routes, "POST", "/api/features/:key/rollout/abort", controllers.AbortRollout)
+	assertRouteExists(suite.T(), routes, "POST", "/api/features/:key/promote", controllers.PromoteFeature)
+	assertRouteExists(suite.T(), routes, "GET", "/api/segments", controllers.GetAllSegments)
+	assertRouteExists(suite.T(), routes, "GET", "/api/segments/:name", controllers.GetSegment)
+	assertRouteExists(suite.T(), routes, "PUT", "/api/segments/:name", controllers.PutSegment)
+	assertRouteExists(suite.T(), routes, "DELETE", "/api/segments/:name", controllers.DeleteSegment)
+	assertRouteExists(suite.T(), routes, "POST", "/api/state", controllers.PostBatchEvaluation)
 	assertRouteExists(suite.T(), routes, "GET", "/status", controllers.GetHealthStatus)
 }
 
@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/robzienert/lever/shared/strutil"
@@ -38,19 +39,74 @@ func Load() {
 	viper.SetDefault("http.addr", ":8500")
 	viper.SetDefault("http.cert", "")
 	viper.SetDefault("http.key", "")
+	viper.SetDefault("http.trustedProxies", []string{})
 	viper.SetDefault("cassandra.keyspace", "lever")
+	viper.SetDefault("bolt.path", "lever.db")
+	viper.SetDefault("sql.dialect", "postgres")
+	viper.SetDefault("sql.dsn", "")
+	viper.SetDefault("file.path", "features")
+	viper.SetDefault("cache.enabled", false)
+	viper.SetDefault("cache.ttl", "10s")
+	viper.SetDefault("cache.maxSize", 10000)
 	viper.SetDefault("statsd.addr", "127.0.0.1:8125")
 	viper.SetDefault("statsd.bufferLength", 100)
+	viper.SetDefault("rollout.interval", "1m")
+	viper.SetDefault("usage.interval", "1m")
+	viper.SetDefault("exposure.sink", "")
+	viper.SetDefault("exposure.bufferSize", 10000)
+	viper.SetDefault("exposure.batchSize", 500)
+	viper.SetDefault("exposure.flushInterval", "5s")
+	viper.SetDefault("exposure.samplePercent", 100)
+	viper.SetDefault("exposure.file.path", "exposures.ndjson")
+	viper.SetDefault("exposure.file.maxSize", "100mb")
+	viper.SetDefault("exposure.http.url", "")
+	viper.SetDefault("exposure.http.timeout", "5s")
 
 	viper.ReadInConfig()
 }
 
 // Validate the set configuration opts.
 func Validate() error {
-	validStores := []string{"cql", "memory"}
+	validStores := []string{"bolt", "cql", "file", "memory", "sql"}
 	if !strutil.StringInSlice(viper.GetString("store"), validStores) {
 		return errors.New("invalid store config")
 	}
+	if viper.GetString("store") == "sql" {
+		validDialects := []string{"postgres", "sqlite3"}
+		if !strutil.StringInSlice(viper.GetString("sql.dialect"), validDialects) {
+			return errors.New("invalid sql dialect config")
+		}
+		if viper.GetString("sql.dsn") == "" {
+			return errors.New("sql store requires a dsn")
+		}
+	}
+	validExposureSinks := []string{"", "file", "http"}
+	if !strutil.StringInSlice(viper.GetString("exposure.sink"), validExposureSinks) {
+		return errors.New("invalid exposure sink config")
+	}
+	if viper.GetString("exposure.sink") == "http" && viper.GetString("exposure.http.url") == "" {
+		return errors.New("exposure http sink requires a url")
+	}
+	// Intervals that are not durations are read as 0, and tickers panic unless
+	// their interval is positive.
+	intervals := []string{"rollout.interval", "usage.interval"}
+	if viper.GetString("exposure.sink") != "" {
+		intervals = append(intervals, "exposure.flushInterval")
+	}
+	for _, key := range intervals {
+		if viper.GetDuration(key) <= 0 {
+			return fmt.Errorf("invalid %s config: must be a positive duration", key)
+		}
+	}
+	// An unbuffered exporter drops nearly every event, and one without a batch
+	// size never flushes by size.
+	if viper.GetString("exposure.sink") != "" {
+		for _, key := range []string{"exposure.bufferSize", "exposure.batchSize"} {
+			if viper.GetInt(key) <= 0 {
+				return fmt.Errorf("invalid %s config: must be positive", key)
+			}
+		}
+	}
 	return nil
 }
 
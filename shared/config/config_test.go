@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Positive(t *testing.T) {
+	defer viper.Reset()
+
+	cases := []struct {
+		key      string
+		value    string
+		sink     string
+		expected bool
+	}{
+		{"rollout.interval", "1m", "", true},
+		{"rollout.interval", "0s", "", false},
+		{"usage.interval", "-1m", "", false},
+		{"usage.interval", "soon", "", false},
+		{"exposure.flushInterval", "0s", "", true},
+		{"exposure.flushInterval", "0s", "file", false},
+		{"exposure.bufferSize", "0", "", true},
+		{"exposure.bufferSize", "0", "file", false},
+		{"exposure.batchSize", "-1", "http", false},
+		{"exposure.batchSize", "500", "file", true},
+	}
+	for i, c := range cases {
+		viper.Reset()
+		viper.Set("store", "memory")
+		viper.Set("rollout.interval", "1m")
+		viper.Set("usage.interval", "1m")
+		viper.Set("exposure.flushInterval", "5s")
+		viper.Set("exposure.bufferSize", 10000)
+		viper.Set("exposure.batchSize", 500)
+		viper.Set("exposure.http.url", "http://localhost")
+		viper.Set("exposure.sink", c.sink)
+		viper.Set(c.key, c.value)
+		assert.Equal(t, c.expected, Validate() == nil, fmt.Sprintf("case %d", i+1))
+	}
+}
@@ -0,0 +1,108 @@
+package semverutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version. Build metadata is discarded, as it has
+// no bearing on precedence.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+}
+
+// Parse a semantic version string. A leading "v" is allowed, and missing minor
+// or patch components default to zero, so "5.2" is the same as "5.2.0".
+func Parse(s string) (*Version, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+
+	v := &Version{}
+	if i := strings.Index(s, "-"); i >= 0 {
+		v.Prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid semantic version: %s", s)
+	}
+	core := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid semantic version: %s", s)
+		}
+		*core[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 if a is lower, equal or higher precedence than b.
+func Compare(a string, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// Compare returns -1, 0 or 1 if v is lower, equal or higher precedence than o.
+func (v *Version) Compare(o *Version) int {
+	if c := compareInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+
+	// A version without a prerelease has higher precedence than one with.
+	if len(v.Prerelease) == 0 || len(o.Prerelease) == 0 {
+		return compareInt(len(o.Prerelease), len(v.Prerelease))
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := comparePrerelease(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(v.Prerelease), len(o.Prerelease))
+}
+
+// Numeric identifiers are compared numerically and always have lower
+// precedence than alphanumeric identifiers, which are compared lexically.
+func comparePrerelease(a string, b string) int {
+	na, aErr := strconv.Atoi(a)
+	nb, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInt(na, nb)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInt(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -1,5 +1,7 @@
 package strutil
 
+import "strings"
+
 // StringInSlice returns whether or not a value is in the given slice.
 func StringInSlice(val string, list []string) bool {
 	for _, s := range list {
@@ -9,3 +11,14 @@ func StringInSlice(val string, list []string) bool {
 	}
 	return false
 }
+
+// SplitList splits a comma-delimited list, dropping empty values.
+func SplitList(val string) []string {
+	var list []string
+	for _, s := range strings.Split(val, ",") {
+		if s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
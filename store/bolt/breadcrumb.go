@@ -0,0 +1,43 @@
+package bolt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/robzienert/lever/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Breadcrumbs are keyed by a big-endian sequence number, so that they are
+// listed in the order they were created.
+type breadcrumbStore struct {
+	db *bolt.DB
+}
+
+func (s *breadcrumbStore) GetList() ([]*model.Breadcrumb, error) {
+	var breadcrumbs []*model.Breadcrumb
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(breadcrumbsBucket).ForEach(func(k, v []byte) error {
+			b := &model.Breadcrumb{}
+			if err := json.Unmarshal(v, b); err != nil {
+				return err
+			}
+			breadcrumbs = append(breadcrumbs, b)
+			return nil
+		})
+	})
+	return breadcrumbs, err
+}
+
+func (s *breadcrumbStore) Create(b *model.Breadcrumb) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(breadcrumbsBucket)
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		return put(bucket, key, b)
+	})
+}
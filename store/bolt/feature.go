@@ -0,0 +1,131 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Features are keyed by namespace and key, separated by a NUL byte so that
+// the features of a namespace can be listed with a prefix scan. Features
+// without a namespace use the empty namespace.
+type featureStore struct {
+	db *bolt.DB
+}
+
+func featureKey(namespace string, key string) []byte {
+	return []byte(namespace + "\x00" + key)
+}
+
+func (s *featureStore) Get(key string) (*model.Feature, error) {
+	return s.GetByNamespace("", key)
+}
+
+func (s *featureStore) GetByNamespace(namespace string, key string) (*model.Feature, error) {
+	var feature *model.Feature
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		feature, err = getFeature(tx.Bucket(featuresBucket), namespace, key)
+		return err
+	})
+	return feature, err
+}
+
+func (s *featureStore) GetList() ([]*model.Feature, error) {
+	return s.GetListByNamespace("")
+}
+
+func (s *featureStore) GetListByNamespace(namespace string) ([]*model.Feature, error) {
+	prefix := featureKey(namespace, "")
+	var features []*model.Feature
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(featuresBucket).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			f := &model.Feature{}
+			if err := json.Unmarshal(v, f); err != nil {
+				return err
+			}
+			features = append(features, f)
+		}
+		return nil
+	})
+	return features, err
+}
+
+func (s *featureStore) GetAll() ([]*model.Feature, error) {
+	var features []*model.Feature
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(featuresBucket).ForEach(func(k, v []byte) error {
+			f := &model.Feature{}
+			if err := json.Unmarshal(v, f); err != nil {
+				return err
+			}
+			features = append(features, f)
+			return nil
+		})
+	})
+	return features, err
+}
+
+func (s *featureStore) Upsert(feature *model.Feature) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(featuresBucket)
+		stored, err := getFeature(b, feature.Namespace, feature.Key)
+		if err != nil {
+			return err
+		}
+		var revision int64
+		if stored != nil {
+			revision = stored.Revision
+		}
+		if feature.Revision != revision {
+			return store.ErrConflict
+		}
+
+		saved := feature.Copy()
+		saved.Revision++
+		return put(b, featureKey(feature.Namespace, feature.Key), saved)
+	})
+	if err != nil {
+		return err
+	}
+	// The caller's feature only moves to the next revision once the transaction
+	// has committed.
+	feature.Revision++
+	return nil
+}
+
+func (s *featureStore) Delete(feature *model.Feature) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(featuresBucket)
+		stored, err := getFeature(b, feature.Namespace, feature.Key)
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			if feature.Revision != 0 {
+				return store.ErrConflict
+			}
+			return nil
+		}
+		if stored.Revision != feature.Revision {
+			return store.ErrConflict
+		}
+		return b.Delete(featureKey(feature.Namespace, feature.Key))
+	})
+}
+
+func getFeature(b *bolt.Bucket, namespace string, key string) (*model.Feature, error) {
+	v := b.Get(featureKey(namespace, key))
+	if v == nil {
+		return nil, nil
+	}
+	f := &model.Feature{}
+	if err := json.Unmarshal(v, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
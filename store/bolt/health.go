@@ -0,0 +1,24 @@
+package bolt
+
+import (
+	"github.com/robzienert/http-healthcheck"
+	bolt "go.etcd.io/bbolt"
+)
+
+type healthProvider struct {
+	db *bolt.DB
+}
+
+// NewHealthProvider creates a health provider that is unhealthy once the
+// database can no longer be read, such as after it has been closed.
+func NewHealthProvider(db *bolt.DB) healthcheck.Provider {
+	return &healthProvider{db: db}
+}
+
+func (p *healthProvider) Name() string { return "bolt" }
+func (p *healthProvider) Start() error { return nil }
+func (p *healthProvider) Close() error { return nil }
+
+func (p *healthProvider) IsHealthy() error {
+	return p.db.View(func(tx *bolt.Tx) error { return nil })
+}
@@ -0,0 +1,52 @@
+package bolt
+
+import (
+	"encoding/json"
+
+	"github.com/robzienert/lever/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+type segmentStore struct {
+	db *bolt.DB
+}
+
+func (s *segmentStore) Get(name string) (*model.Segment, error) {
+	var segment *model.Segment
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(segmentsBucket).Get([]byte(name))
+		if v == nil {
+			return nil
+		}
+		segment = &model.Segment{}
+		return json.Unmarshal(v, segment)
+	})
+	return segment, err
+}
+
+func (s *segmentStore) GetList() ([]*model.Segment, error) {
+	var segments []*model.Segment
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(segmentsBucket).ForEach(func(k, v []byte) error {
+			segment := &model.Segment{}
+			if err := json.Unmarshal(v, segment); err != nil {
+				return err
+			}
+			segments = append(segments, segment)
+			return nil
+		})
+	})
+	return segments, err
+}
+
+func (s *segmentStore) Upsert(segment *model.Segment) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return put(tx.Bucket(segmentsBucket), []byte(segment.Name), segment)
+	})
+}
+
+func (s *segmentStore) Delete(segment *model.Segment) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(segmentsBucket).Delete([]byte(segment.Name))
+	})
+}
@@ -0,0 +1,85 @@
+package bolt
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/robzienert/http-healthcheck"
+	"github.com/robzienert/lever/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	breadcrumbsBucket = []byte("breadcrumbs")
+	featuresBucket    = []byte("features")
+	segmentsBucket    = []byte("segments")
+	usageBucket       = []byte("usage")
+)
+
+// openTimeout is how long to wait for the lock on a database file that is
+// already open in another process, rather than blocking forever.
+const openTimeout = 5 * time.Second
+
+// StoreResponse encapsulates all resulting objects from a Bolt Store load.
+//
+// The database is returned so that its Closer impl can be deferred from the
+// main method.
+type StoreResponse struct {
+	Store          store.Store
+	DB             *bolt.DB
+	HealthProvider healthcheck.Provider
+}
+
+// Load a new Bolt storage backend from the database file at path, creating
+// the file if it does not exist.
+func Load(path string) (*StoreResponse, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &StoreResponse{
+		Store:          s,
+		DB:             db,
+		HealthProvider: NewHealthProvider(db),
+	}, nil
+}
+
+// New will initialize a new Bolt storage repository, creating its buckets if
+// they do not exist yet.
+func New(db *bolt.DB) (store.Store, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{breadcrumbsBucket, featuresBucket, segmentsBucket, usageBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return store.New(
+		"bolt",
+		&breadcrumbStore{db: db},
+		&featureStore{db: db},
+		&segmentStore{db: db},
+		&usageStore{db: db},
+	), nil
+}
+
+// put stores the value as JSON.
+func put(b *bolt.Bucket, key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
@@ -0,0 +1,140 @@
+package bolt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func load(t *testing.T) (*StoreResponse, func()) {
+	dir, err := ioutil.TempDir("", "lever-bolt")
+	assert.NoError(t, err)
+	resp, err := Load(filepath.Join(dir, "lever.db"))
+	assert.NoError(t, err)
+	return resp, func() {
+		resp.DB.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestFeatureStore(t *testing.T) {
+	resp, cleanup := load(t)
+	defer cleanup()
+	s := resp.Store.Features()
+
+	global := &model.Feature{Key: "foo", Gate: &model.Gate{Value: "true"}}
+	namespaced := &model.Feature{Namespace: "api", Key: "foo", Gate: &model.Gate{Value: "false"}}
+	assert.NoError(t, s.Upsert(global))
+	assert.NoError(t, s.Upsert(namespaced))
+	assert.EqualValues(t, 1, global.Revision)
+
+	f, err := s.Get("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "true", f.Gate.Value)
+	f, err = s.GetByNamespace("api", "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "false", f.Gate.Value)
+	f, err = s.GetByNamespace("api", "bar")
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+
+	list, err := s.GetList()
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	list, err = s.GetListByNamespace("api")
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	list, err = s.GetListByNamespace("ap")
+	assert.NoError(t, err)
+	assert.Len(t, list, 0)
+	list, err = s.GetAll()
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	assert.NoError(t, s.Delete(namespaced))
+	f, err = s.GetByNamespace("api", "foo")
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+}
+
+func TestFeatureStore_Revisions(t *testing.T) {
+	resp, cleanup := load(t)
+	defer cleanup()
+	s := resp.Store.Features()
+
+	assert.NoError(t, s.Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{}}))
+	first, _ := s.Get("foo")
+	second, _ := s.Get("foo")
+
+	assert.NoError(t, s.Upsert(first))
+	assert.EqualValues(t, 2, first.Revision)
+	assert.Equal(t, store.ErrConflict, s.Upsert(second), "stale upsert")
+	assert.Equal(t, store.ErrConflict, s.Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{}}), "create over existing")
+	assert.EqualValues(t, 1, second.Revision, "failed upsert changed revision")
+
+	assert.Equal(t, store.ErrConflict, s.Delete(second), "stale delete")
+	assert.NoError(t, s.Delete(first))
+	assert.Equal(t, store.ErrConflict, s.Delete(first), "delete of deleted feature")
+}
+
+func TestStore_Persists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lever-bolt")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "lever.db")
+
+	resp, err := Load(path)
+	assert.NoError(t, err)
+	s := resp.Store
+	assert.NoError(t, s.Features().Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{}}))
+	assert.NoError(t, s.Segments().Upsert(&model.Segment{Name: "beta", Actors: []string{"a"}}))
+	for _, action := range []string{"first", "second"} {
+		b := model.NewBreadcrumb(action, "me")
+		b.Fields["key"] = "foo"
+		assert.NoError(t, s.Breadcrumbs().Create(b))
+	}
+	now := time.Now().UTC()
+	u := &model.Usage{Key: "foo", ClientID: "app", Outcome: model.EnabledOutcome, Count: 2, LastEvaluated: now}
+	for i := 0; i < 2; i++ {
+		failed, err := s.Usage().Add([]*model.Usage{u})
+		assert.NoError(t, err)
+		assert.Empty(t, failed)
+	}
+	assert.NoError(t, resp.HealthProvider.IsHealthy())
+	assert.NoError(t, resp.DB.Close())
+	assert.Error(t, resp.HealthProvider.IsHealthy(), "closed database is healthy")
+
+	resp, err = Load(path)
+	assert.NoError(t, err)
+	defer resp.DB.Close()
+	s = resp.Store
+
+	f, err := s.Features().Get("foo")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, f.Revision)
+
+	segment, err := s.Segments().Get("beta")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, segment.Actors)
+
+	breadcrumbs, err := s.Breadcrumbs().GetList()
+	assert.NoError(t, err)
+	if assert.Len(t, breadcrumbs, 2) {
+		assert.Equal(t, "first", breadcrumbs[0].Action)
+		assert.Equal(t, "second", breadcrumbs[1].Action)
+		assert.Equal(t, "foo", breadcrumbs[0].Fields["key"])
+	}
+
+	usage, err := s.Usage().GetList()
+	assert.NoError(t, err)
+	if assert.Len(t, usage, 1) {
+		assert.EqualValues(t, 4, usage[0].Count)
+		assert.True(t, now.Equal(usage[0].LastEvaluated))
+	}
+}
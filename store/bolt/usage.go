@@ -0,0 +1,57 @@
+package bolt
+
+import (
+	"encoding/json"
+
+	"github.com/robzienert/lever/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+type usageStore struct {
+	db *bolt.DB
+}
+
+func usageKey(u *model.Usage) []byte {
+	return []byte(u.Namespace + "\x00" + u.Key + "\x00" + u.ClientID + "\x00" + u.Outcome)
+}
+
+func (s *usageStore) GetList() ([]*model.Usage, error) {
+	var all []*model.Usage
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(usageBucket).ForEach(func(k, v []byte) error {
+			u := &model.Usage{}
+			if err := json.Unmarshal(v, u); err != nil {
+				return err
+			}
+			all = append(all, u)
+			return nil
+		})
+	})
+	return all, err
+}
+
+// Add increments the counts in a single transaction, so either all or none of
+// the usage is added.
+func (s *usageStore) Add(usage []*model.Usage) ([]*model.Usage, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(usageBucket)
+		for _, u := range usage {
+			key := usageKey(u)
+			stored := *u
+			if v := b.Get(key); v != nil {
+				if err := json.Unmarshal(v, &stored); err != nil {
+					return err
+				}
+				stored.Add(u)
+			}
+			if err := put(b, key, &stored); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return usage, err
+	}
+	return nil, nil
+}
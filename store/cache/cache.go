@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+	"github.com/karlseguin/ccache"
+	"github.com/robzienert/lever/metrics"
+	"github.com/robzienert/lever/store"
+)
+
+// Spec configures the cache. TTL bounds how long changes made through other
+// servers take to be seen, and MaxSize is the number of entries kept.
+type Spec struct {
+	TTL     time.Duration
+	MaxSize int64
+	StatsD  *statsd.Client
+}
+
+// New wraps the features and segments of a store with a read-through cache.
+// Lookups of missing features and segments are cached as well. Entries are
+// invalidated when features and segments are changed through the returned
+// store; breadcrumbs and usage are not cached.
+//
+// Hits and misses are counted in the cache.hit and cache.miss metrics, tagged
+// with the cached entity.
+func New(s store.Store, spec Spec) store.Store {
+	return store.New(
+		s.Name()+"+cache",
+		s.Breadcrumbs(),
+		&featureStore{
+			cache: newCache(spec, "features"),
+			next:  s.Features(),
+		},
+		&segmentStore{
+			cache: newCache(spec, "segments"),
+			next:  s.Segments(),
+		},
+		s.Usage(),
+	)
+}
+
+// cache counts its invalidations, so that a value loaded while an entry was
+// invalidated is not cached: it may have been read before the change that
+// caused the invalidation, and would be served until the TTL.
+type cache struct {
+	entries *ccache.Cache
+	ttl     time.Duration
+	statsd  *statsd.Client
+	tag     string
+
+	lock       sync.Mutex
+	generation uint64
+}
+
+func newCache(spec Spec, entity string) *cache {
+	return &cache{
+		entries: ccache.New(ccache.Configure().MaxSize(spec.MaxSize)),
+		ttl:     spec.TTL,
+		statsd:  spec.StatsD,
+		tag:     "cache:" + entity,
+	}
+}
+
+// get returns the cached value of the key, or loads and caches it. The loaded
+// value is not cached if any entry was invalidated while it was loaded.
+func (c *cache) get(key string, load func() (interface{}, error)) (interface{}, error) {
+	if item := c.entries.Get(key); item != nil && !item.Expired() {
+		metrics.Incr(c.statsd, "cache.hit", c.tag)
+		return item.Value(), nil
+	}
+	metrics.Incr(c.statsd, "cache.miss", c.tag)
+
+	c.lock.Lock()
+	generation := c.generation
+	c.lock.Unlock()
+
+	v, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if generation == c.generation {
+		c.entries.Set(key, v, c.ttl)
+	}
+	return v, nil
+}
+
+func (c *cache) delete(keys ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.generation++
+	for _, k := range keys {
+		c.entries.Delete(k)
+	}
+}
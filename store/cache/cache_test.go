@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/robzienert/lever/store/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func countingStore(features map[string]*model.Feature, calls map[string]int) *mock.FeatureStore {
+	return &mock.FeatureStore{
+		GetFn: func(namespace string, key string) (*model.Feature, error) {
+			calls["get"]++
+			return features[namespace+"/"+key], nil
+		},
+		GetListFn: func(namespace string) ([]*model.Feature, error) {
+			calls["list"]++
+			var list []*model.Feature
+			for _, f := range features {
+				if f.Namespace == namespace {
+					list = append(list, f)
+				}
+			}
+			return list, nil
+		},
+		UpsertFn: func(feature *model.Feature) error {
+			features[feature.Namespace+"/"+feature.Key] = feature
+			return nil
+		},
+		DeleteFn: func(feature *model.Feature) error {
+			delete(features, feature.Namespace+"/"+feature.Key)
+			return nil
+		},
+	}
+}
+
+func TestFeatureStore_ReadThrough(t *testing.T) {
+	features := map[string]*model.Feature{"api/foo": {Namespace: "api", Key: "foo", Gate: &model.Gate{Value: "true"}}}
+	calls := make(map[string]int)
+	s := New(mock.LoadFeatureStore(countingStore(features, calls)), Spec{TTL: time.Minute, MaxSize: 100}).Features()
+
+	f, err := s.GetByNamespace("api", "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "true", f.Gate.Value)
+	f.Gate.Value = "false"
+	f, _ = s.GetByNamespace("api", "foo")
+	assert.Equal(t, "true", f.Gate.Value, "cached features are copied")
+	assert.Equal(t, 1, calls["get"])
+
+	f, err = s.Get("missing")
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+	s.Get("missing")
+	assert.Equal(t, 2, calls["get"], "missing features are cached")
+
+	list, _ := s.GetListByNamespace("api")
+	assert.Len(t, list, 1)
+	s.GetListByNamespace("api")
+	assert.Equal(t, 1, calls["list"])
+
+	assert.NoError(t, s.Upsert(&model.Feature{Namespace: "api", Key: "foo", Gate: &model.Gate{Value: "false"}}))
+	f, _ = s.GetByNamespace("api", "foo")
+	assert.Equal(t, "false", f.Gate.Value)
+	s.GetListByNamespace("api")
+	assert.Equal(t, 3, calls["get"])
+	assert.Equal(t, 2, calls["list"])
+
+	assert.NoError(t, s.Delete(f))
+	f, _ = s.GetByNamespace("api", "foo")
+	assert.Nil(t, f)
+	list, _ = s.GetListByNamespace("api")
+	assert.Empty(t, list)
+}
+
+func TestFeatureStore_WriteDuringLoad(t *testing.T) {
+	features := map[string]*model.Feature{"/foo": {Key: "foo", Gate: &model.Gate{Value: "false"}}}
+	calls := make(map[string]int)
+	backing := countingStore(features, calls)
+	s := New(mock.LoadFeatureStore(backing), Spec{TTL: time.Minute, MaxSize: 100}).Features()
+
+	// The feature is changed after it was read, but before the read is cached.
+	get := backing.GetFn
+	backing.GetFn = func(namespace string, key string) (*model.Feature, error) {
+		f, err := get(namespace, key)
+		if calls["get"] == 1 {
+			assert.NoError(t, s.Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{Value: "true"}}))
+		}
+		return f, err
+	}
+
+	f, _ := s.Get("foo")
+	assert.Equal(t, "false", f.Gate.Value)
+	f, _ = s.Get("foo")
+	assert.Equal(t, "true", f.Gate.Value, "stale read was cached")
+	s.Get("foo")
+	assert.Equal(t, 2, calls["get"])
+}
+
+func TestFeatureStore_TTL(t *testing.T) {
+	features := map[string]*model.Feature{"/foo": {Key: "foo", Gate: &model.Gate{}}}
+	calls := make(map[string]int)
+	s := New(mock.LoadFeatureStore(countingStore(features, calls)), Spec{TTL: time.Millisecond, MaxSize: 100}).Features()
+
+	s.Get("foo")
+	time.Sleep(5 * time.Millisecond)
+	s.Get("foo")
+	assert.Equal(t, 2, calls["get"])
+}
+
+func TestFeatureStore_Errors(t *testing.T) {
+	calls := 0
+	s := New(mock.LoadFeatureStore(&mock.FeatureStore{
+		GetFn: func(namespace string, key string) (*model.Feature, error) {
+			calls++
+			return nil, errors.New("unavailable")
+		},
+	}), Spec{TTL: time.Minute, MaxSize: 100}).Features()
+
+	_, err := s.Get("foo")
+	assert.Error(t, err)
+	_, err = s.Get("foo")
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls, "errors are not cached")
+}
+
+func TestSegmentStore_Invalidate(t *testing.T) {
+	memStore := memory.Load()
+	s := New(memStore, Spec{TTL: time.Minute, MaxSize: 100})
+
+	segment, err := s.Segments().Get("beta")
+	assert.NoError(t, err)
+	assert.Nil(t, segment)
+
+	assert.NoError(t, s.Segments().Upsert(&model.Segment{Name: "beta", Actors: []string{"one"}}))
+	segment, _ = s.Segments().Get("beta")
+	assert.Equal(t, []string{"one"}, segment.Actors)
+
+	memStore.Segments().Upsert(&model.Segment{Name: "beta", Actors: []string{"two"}})
+	segment, _ = s.Segments().Get("beta")
+	assert.Equal(t, []string{"one"}, segment.Actors, "changes through other stores are not seen until the TTL")
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+)
+
+// featureStore caches single features and the feature lists of namespaces.
+// Features are copied out of the cache, so that callers changing them do not
+// change the cached features.
+type featureStore struct {
+	cache *cache
+	next  store.FeatureStore
+}
+
+func featureKey(namespace string, key string) string {
+	return "feature:" + namespace + "/" + key
+}
+
+func listKey(namespace string) string {
+	return "list:" + namespace
+}
+
+func (s *featureStore) Get(key string) (*model.Feature, error) {
+	return s.GetByNamespace("", key)
+}
+
+func (s *featureStore) GetByNamespace(namespace string, key string) (*model.Feature, error) {
+	v, err := s.cache.get(featureKey(namespace, key), func() (interface{}, error) {
+		if namespace == "" {
+			return s.next.Get(key)
+		}
+		return s.next.GetByNamespace(namespace, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	f := v.(*model.Feature)
+	if f == nil {
+		return nil, nil
+	}
+	return f.Copy(), nil
+}
+
+func (s *featureStore) GetList() ([]*model.Feature, error) {
+	return s.GetListByNamespace("")
+}
+
+func (s *featureStore) GetListByNamespace(namespace string) ([]*model.Feature, error) {
+	v, err := s.cache.get(listKey(namespace), func() (interface{}, error) {
+		if namespace == "" {
+			return s.next.GetList()
+		}
+		return s.next.GetListByNamespace(namespace)
+	})
+	if err != nil {
+		return nil, err
+	}
+	cached := v.([]*model.Feature)
+	if cached == nil {
+		return nil, nil
+	}
+	features := make([]*model.Feature, len(cached))
+	for i, f := range cached {
+		features[i] = f.Copy()
+	}
+	return features, nil
+}
+
+// GetAll is not cached, as it is only used outside of evaluations.
+func (s *featureStore) GetAll() ([]*model.Feature, error) {
+	return s.next.GetAll()
+}
+
+func (s *featureStore) Upsert(feature *model.Feature) error {
+	defer s.cache.delete(featureKey(feature.Namespace, feature.Key), listKey(feature.Namespace))
+	return s.next.Upsert(feature)
+}
+
+func (s *featureStore) Delete(feature *model.Feature) error {
+	defer s.cache.delete(featureKey(feature.Namespace, feature.Key), listKey(feature.Namespace))
+	return s.next.Delete(feature)
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+)
+
+// segmentStore caches single segments, which are looked up when evaluating
+// segment gates. Segments are copied out of the cache.
+type segmentStore struct {
+	cache *cache
+	next  store.SegmentStore
+}
+
+func (s *segmentStore) Get(name string) (*model.Segment, error) {
+	v, err := s.cache.get("segment:"+name, func() (interface{}, error) {
+		return s.next.Get(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	segment := v.(*model.Segment)
+	if segment == nil {
+		return nil, nil
+	}
+	c := *segment
+	return &c, nil
+}
+
+// GetList is not cached, as it is only used outside of evaluations.
+func (s *segmentStore) GetList() ([]*model.Segment, error) {
+	return s.next.GetList()
+}
+
+func (s *segmentStore) Upsert(segment *model.Segment) error {
+	defer s.cache.delete("segment:" + segment.Name)
+	return s.next.Upsert(segment)
+}
+
+func (s *segmentStore) Delete(segment *model.Segment) error {
+	defer s.cache.delete("segment:" + segment.Name)
+	return s.next.Delete(segment)
+}
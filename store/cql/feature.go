@@ -2,6 +2,7 @@ package cql
 
 import (
 	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
 	"github.com/Sirupsen/logrus"
 	"github.com/gocql/gocql"
 )
@@ -40,6 +41,18 @@ func (s *featureStore) GetListByNamespace(namespace string) ([]*model.Feature, e
 	return s.all("SELECT * FROM features_namespaced WHERE namespace = ?", namespace)
 }
 
+func (s *featureStore) GetAll() ([]*model.Feature, error) {
+	global, err := s.all("SELECT * FROM features")
+	if err != nil {
+		return nil, err
+	}
+	namespaced, err := s.all("SELECT * FROM features_namespaced")
+	if err != nil {
+		return nil, err
+	}
+	return append(global, namespaced...), nil
+}
+
 func (s *featureStore) all(query string, args ...interface{}) ([]*model.Feature, error) {
 	iter := s.session.Query(query, args...).Iter()
 
@@ -60,49 +73,136 @@ func (s *featureStore) all(query string, args ...interface{}) ([]*model.Feature,
 	return all, nil
 }
 
-func (s *featureStore) Upsert(feature *model.Feature) (err error) {
+// featureColumns are the columns written on upsert, in the same order as the
+// values returned by featureValues.
+const featureColumns = `type = ?, value = ?, off_value = ?, schema = ?, gate_value = ?, gate_groups = ?, gate_actors = ?,
+gate_deny_groups = ?, gate_deny_actors = ?, gate_segments = ?, gate_cidrs = ?,
+gate_actor_percent = ?, gate_percent_of_everyone = ?, gate_percent_of_time = ?, gate_rules = ?,
+gate_schedule = ?, gate_expression = ?, gate_seed = ?, gate_custom = ?, environments = ?, variants = ?, prerequisites = ?, rollout = ?, lifecycle = ?,
+date_created = ?, last_updated = ?`
+
+func featureValues(feature *model.Feature) ([]interface{}, error) {
+	rules, err := marshalJSONColumn(feature.Gate.Rules)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := marshalJSONColumn(feature.Gate.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	expression, err := marshalJSONColumn(feature.Gate.Expression)
+	if err != nil {
+		return nil, err
+	}
+	custom, err := marshalJSONColumn(feature.Gate.Custom)
+	if err != nil {
+		return nil, err
+	}
+	environments, err := marshalJSONColumn(feature.Environments)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := marshalJSONColumn(feature.Variants)
+	if err != nil {
+		return nil, err
+	}
+	prerequisites, err := marshalJSONColumn(feature.Prerequisites)
+	if err != nil {
+		return nil, err
+	}
+	rollout, err := marshalJSONColumn(feature.Rollout)
+	if err != nil {
+		return nil, err
+	}
+	lifecycle, err := marshalJSONColumn(feature.Lifecycle)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		feature.Type,
+		feature.Value,
+		feature.OffValue,
+		string(feature.Schema),
+		feature.Gate.Value,
+		feature.Gate.Groups,
+		feature.Gate.Actors,
+		feature.Gate.DenyGroups,
+		feature.Gate.DenyActors,
+		feature.Gate.Segments,
+		feature.Gate.CIDRs,
+		feature.Gate.ActorPercent,
+		feature.Gate.PercentOfEveryone,
+		feature.Gate.PercentOfTime,
+		rules,
+		schedule,
+		expression,
+		feature.Gate.Seed,
+		custom,
+		environments,
+		variants,
+		prerequisites,
+		rollout,
+		lifecycle,
+		feature.DateCreated,
+		feature.LastUpdated,
+	}, nil
+}
+
+func (s *featureStore) Upsert(feature *model.Feature) error {
+	values, err := featureValues(feature)
+	if err != nil {
+		return err
+	}
+	values = append(values, feature.Revision+1)
+	condition, conditionValues := revisionCondition(feature.Revision)
+
+	var query string
 	if feature.Namespace == "" {
-		err = s.session.Query(
-			`UPDATE features SET type = ?, value = ?, gate_value = ?, gate_groups = ?, gate_actors = ?,
-gate_actor_percent = ?, gate_percent_of_time = ?, date_created = ?, last_updated = ?
-WHERE key = ?`,
-			feature.Type,
-			feature.Value,
-			feature.Gate.Value,
-			feature.Gate.Groups,
-			feature.Gate.Actors,
-			feature.Gate.ActorPercent,
-			feature.Gate.PercentOfTime,
-			feature.DateCreated,
-			feature.LastUpdated,
-			feature.Key,
-		).Exec()
+		query = `UPDATE features SET ` + featureColumns + `, revision = ? WHERE key = ? ` + condition
+		values = append(values, feature.Key)
 	} else {
-		err = s.session.Query(
-			`UPDATE features_namespaced SET type = ?, value = ?, gate_value = ?, gate_groups = ?,
-gate_actors = ?, gate_actor_percent = ?, gate_percent_of_time = ?, date_created = ?, last_updated = ?
-WHERE namespace = ? AND key = ?`,
-			feature.Type,
-			feature.Value,
-			feature.Gate.Value,
-			feature.Gate.Groups,
-			feature.Gate.Actors,
-			feature.Gate.ActorPercent,
-			feature.Gate.PercentOfTime,
-			feature.DateCreated,
-			feature.LastUpdated,
-			feature.Namespace,
-			feature.Key,
-		).Exec()
-	}
-	return
+		query = `UPDATE features_namespaced SET ` + featureColumns + `, revision = ? WHERE namespace = ? AND key = ? ` + condition
+		values = append(values, feature.Namespace, feature.Key)
+	}
+	if err := s.exec(query, append(values, conditionValues...)...); err != nil {
+		return err
+	}
+	feature.Revision++
+	return nil
 }
 
-func (s *featureStore) Delete(feature *model.Feature) (err error) {
+func (s *featureStore) Delete(feature *model.Feature) error {
+	condition, conditionValues := revisionCondition(feature.Revision)
 	if feature.Namespace == "" {
-		err = s.session.Query("DELETE FROM features WHERE key = ?", feature.Key).Exec()
-	} else {
-		err = s.session.Query("DELETE FROM features_namespaced WHERE namespace = ? AND key = ?", feature.Namespace, feature.Key).Exec()
+		return s.exec("DELETE FROM features WHERE key = ? "+condition, append([]interface{}{feature.Key}, conditionValues...)...)
+	}
+	return s.exec("DELETE FROM features_namespaced WHERE namespace = ? AND key = ? "+condition, append([]interface{}{feature.Namespace, feature.Key}, conditionValues...)...)
+}
+
+// revisionCondition is the lightweight transaction condition for changing a
+// feature at a revision. Features without a revision, including those that do
+// not exist yet or were saved before revisions existed, have a null revision.
+func revisionCondition(revision int64) (string, []interface{}) {
+	if revision == 0 {
+		return "IF revision = null", nil
+	}
+	return "IF revision = ?", []interface{}{revision}
+}
+
+// exec runs a lightweight transaction, returning store.ErrConflict if its
+// condition was not met.
+func (s *featureStore) exec(query string, args ...interface{}) error {
+	applied, err := s.session.Query(query, args...).MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err": err,
+			"q":   query,
+			"a":   args,
+		}).Error("Could not execute CQL query")
+		return err
+	}
+	if !applied {
+		return store.ErrConflict
 	}
-	return
+	return nil
 }
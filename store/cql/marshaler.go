@@ -1,6 +1,7 @@
 package cql
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -55,9 +56,87 @@ func marshalFeature(d cqlResult) *model.Feature {
 	if v, ok := d["namespace"]; ok {
 		f.Namespace = v.(string)
 	}
+	if v, ok := d["gate_deny_groups"]; ok {
+		f.Gate.DenyGroups = v.([]string)
+	}
+	if v, ok := d["gate_deny_actors"]; ok {
+		f.Gate.DenyActors = v.([]string)
+	}
+	if v, ok := d["gate_segments"]; ok {
+		f.Gate.Segments = v.([]string)
+	}
+	if v, ok := d["gate_cidrs"]; ok {
+		f.Gate.SetCIDRs(v.([]string))
+	}
+	if v, ok := d["gate_percent_of_everyone"]; ok {
+		f.Gate.PercentOfEveryone = v.(int)
+	}
+	if v, ok := d["gate_seed"]; ok {
+		f.Gate.Seed = v.(string)
+	}
+	if v, ok := d["revision"]; ok {
+		f.Revision = v.(int64)
+	}
+	if v, ok := d["off_value"]; ok {
+		f.OffValue = v.(string)
+	}
+	if v, ok := d["schema"]; ok && v.(string) != "" {
+		f.Schema = json.RawMessage(v.(string))
+	}
+	unmarshalJSONColumn(d, "gate_rules", &f.Gate.Rules)
+	unmarshalJSONColumn(d, "gate_schedule", &f.Gate.Schedule)
+	unmarshalJSONColumn(d, "gate_expression", &f.Gate.Expression)
+	unmarshalJSONColumn(d, "gate_custom", &f.Gate.Custom)
+	unmarshalJSONColumn(d, "environments", &f.Environments)
+	unmarshalJSONColumn(d, "variants", &f.Variants)
+	unmarshalJSONColumn(d, "prerequisites", &f.Prerequisites)
+	unmarshalJSONColumn(d, "rollout", &f.Rollout)
+	unmarshalJSONColumn(d, "lifecycle", &f.Lifecycle)
 	return f
 }
 
+func marshalSegment(d cqlResult) *model.Segment {
+	if d == nil || len(d) == 0 {
+		return nil
+	}
+	defer recoverMarshalPanic("segment", d)
+
+	s := &model.Segment{
+		Name:        d["name"].(string),
+		Actors:      d["actors"].([]string),
+		Groups:      d["groups"].([]string),
+		DateCreated: d["date_created"].(time.Time),
+		LastUpdated: d["last_updated"].(time.Time),
+	}
+	unmarshalJSONColumn(d, "rules", &s.Rules)
+	return s
+}
+
+// marshalJSONColumn encodes nested structures that are stored as JSON text
+// columns. Empty values are stored as an empty string.
+func marshalJSONColumn(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if s := string(b); s != "null" && s != "[]" && s != "{}" {
+		return s, nil
+	}
+	return "", nil
+}
+
+// unmarshalJSONColumn decodes a JSON text column into v. Columns that are
+// missing or empty, such as those added by a later migration, leave v as is.
+func unmarshalJSONColumn(d cqlResult, column string, v interface{}) {
+	s, ok := d[column].(string)
+	if !ok || s == "" {
+		return
+	}
+	if err := json.Unmarshal([]byte(s), v); err != nil {
+		panic(err)
+	}
+}
+
 func recoverMarshalPanic(marshaler string, dat cqlResult) {
 	if r := recover(); r != nil {
 		var err error
@@ -1,8 +1,11 @@
 package cql
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/robzienert/lever/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,8 +17,108 @@ func TestMarshalFeature_NilOnEmpty(t *testing.T) {
 	assert.Nil(t, marshalFeature(cqlResult{}))
 }
 
+func TestMarshalSegment(t *testing.T) {
+	assert.Nil(t, marshalSegment(cqlResult{}))
+
+	rules := []model.Rule{{Attribute: "plan", Operator: model.EqualsOperator, Values: []string{"pro"}}}
+	encoded, err := marshalJSONColumn(rules)
+	assert.NoError(t, err)
+	s := marshalSegment(cqlResult{
+		"name":         "beta",
+		"actors":       []string{"one"},
+		"groups":       []string{},
+		"rules":        encoded,
+		"date_created": time.Now(),
+		"last_updated": time.Now(),
+	})
+	assert.NotNil(t, s)
+	assert.Equal(t, "beta", s.Name)
+	assert.Equal(t, rules, s.Rules)
+}
+
 func TestMarshalPanicRecovery(t *testing.T) {
 	assert.NotPanics(t, func() {
 		assert.Nil(t, marshalFeature(cqlResult{"foo": "bar"}))
 	})
 }
+
+func TestMarshalFeature_JSONColumns(t *testing.T) {
+	rules := []model.Rule{{Attribute: "country", Operator: model.InOperator, Values: []string{"DE", "FR"}}}
+	encoded, err := marshalJSONColumn(rules)
+	assert.NoError(t, err)
+	variants := []model.Variant{{Name: "control", Weight: 50, Value: "blue"}, {Name: "treatment", Weight: 50, Value: "green"}}
+	encodedVariants, err := marshalJSONColumn(variants)
+	assert.NoError(t, err)
+	schedule := &model.Schedule{Windows: []model.Window{{Days: []string{"monday"}, Start: "09:00", End: "17:00"}}}
+	encodedSchedule, err := marshalJSONColumn(schedule)
+	assert.NoError(t, err)
+	prerequisites := []model.Prerequisite{{Namespace: "mobile", Key: "bar", Enabled: true}}
+	encodedPrerequisites, err := marshalJSONColumn(prerequisites)
+	assert.NoError(t, err)
+	rollout := &model.Rollout{Steps: []int{1, 5, 100}, Interval: "24h", State: model.RolloutPausedState, Step: 1}
+	encodedRollout, err := marshalJSONColumn(rollout)
+	assert.NoError(t, err)
+	custom := map[string]json.RawMessage{"region": json.RawMessage(`{"regions":["eu-west-1"]}`)}
+	encodedCustom, err := marshalJSONColumn(custom)
+	assert.NoError(t, err)
+	environments := map[string]*model.Gate{"staging": {Value: "true"}}
+	encodedEnvironments, err := marshalJSONColumn(environments)
+	assert.NoError(t, err)
+	expression := &model.Expression{Not: &model.Expression{Gate: &model.Gate{Groups: []string{"staff"}}}}
+	encodedExpression, err := marshalJSONColumn(expression)
+	assert.NoError(t, err)
+	removeBy := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	lifecycle := &model.Lifecycle{Kind: model.TemporaryLifecycle, Owner: "search", RemoveBy: &removeBy}
+	encodedLifecycle, err := marshalJSONColumn(lifecycle)
+	assert.NoError(t, err)
+
+	d := cqlResult{
+		"key":                  "foo",
+		"type":                 "java.lang.Boolean",
+		"value":                "true",
+		"gate_value":           "",
+		"gate_groups":          []string{},
+		"gate_actors":          []string{},
+		"gate_deny_actors":     []string{"broken"},
+		"gate_cidrs":           []string{"10.0.0.0/8"},
+		"gate_actor_percent":   0,
+		"gate_percent_of_time": 0,
+		"gate_rules":           encoded,
+		"gate_schedule":        encodedSchedule,
+		"off_value":            "false",
+		"revision":             int64(3),
+		"schema":               `{"type":"boolean"}`,
+		"gate_expression":      encodedExpression,
+		"gate_custom":          encodedCustom,
+		"environments":         encodedEnvironments,
+		"variants":             encodedVariants,
+		"prerequisites":        encodedPrerequisites,
+		"rollout":              encodedRollout,
+		"lifecycle":            encodedLifecycle,
+		"date_created":         time.Now(),
+		"last_updated":         time.Now(),
+	}
+	f := marshalFeature(d)
+	assert.NotNil(t, f)
+	assert.Equal(t, rules, f.Gate.Rules)
+	assert.Equal(t, variants, f.Variants)
+	assert.Equal(t, schedule, f.Gate.Schedule)
+	assert.Equal(t, prerequisites, f.Prerequisites)
+	assert.Equal(t, rollout, f.Rollout)
+	assert.Equal(t, "false", f.OffValue)
+	assert.Equal(t, int64(3), f.Revision)
+	assert.Equal(t, json.RawMessage(`{"type":"boolean"}`), f.Schema)
+	assert.Equal(t, expression, f.Gate.Expression)
+	assert.Equal(t, custom, f.Gate.Custom)
+	assert.Equal(t, environments, f.Environments)
+	assert.Equal(t, lifecycle, f.Lifecycle)
+	assert.Equal(t, []string{"broken"}, f.Gate.DenyActors)
+	assert.Equal(t, []string{"10.0.0.0/8"}, f.Gate.CIDRs)
+	assert.Empty(t, f.Gate.DenyGroups)
+
+	d["gate_rules"] = ""
+	d["gate_schedule"] = ""
+	f = marshalFeature(d)
+	assert.Empty(t, f.Gate.Rules)
+	assert.Nil(t, f.Gate.Schedule)
+}
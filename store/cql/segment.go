@@ -0,0 +1,61 @@
+package cql
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/gocql/gocql"
+	"github.com/robzienert/lever/model"
+)
+
+type segmentStore struct {
+	session *gocql.Session
+}
+
+func (s *segmentStore) Get(name string) (*model.Segment, error) {
+	query := "SELECT * FROM segments WHERE name = ?"
+	data := make(cqlResult, 0)
+	err := s.session.Query(query, name).MapScan(data)
+	if err != nil && err.Error() != notFoundError {
+		logrus.WithFields(logrus.Fields{
+			"err": err,
+			"q":   query,
+			"a":   name,
+		}).Error("Could not execute CQL query")
+		return nil, err
+	}
+	return marshalSegment(data), nil
+}
+
+func (s *segmentStore) GetList() ([]*model.Segment, error) {
+	query := "SELECT * FROM segments"
+	iter := s.session.Query(query).Iter()
+
+	var all []*model.Segment
+	data := make(cqlResult, 0)
+	for iter.MapScan(data) {
+		all = append(all, marshalSegment(data))
+	}
+	if err := iter.Close(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err": err,
+			"q":   query,
+		}).Error("Could not execute CQL query")
+		return nil, err
+	}
+
+	return all, nil
+}
+
+func (s *segmentStore) Upsert(segment *model.Segment) error {
+	rules, err := marshalJSONColumn(segment.Rules)
+	if err != nil {
+		return err
+	}
+	return s.session.Query(
+		`UPDATE segments SET actors = ?, groups = ?, rules = ?, date_created = ?, last_updated = ? WHERE name = ?`,
+		segment.Actors, segment.Groups, rules, segment.DateCreated, segment.LastUpdated, segment.Name,
+	).Exec()
+}
+
+func (s *segmentStore) Delete(segment *model.Segment) error {
+	return s.session.Query("DELETE FROM segments WHERE name = ?", segment.Name).Exec()
+}
@@ -68,5 +68,7 @@ func New(session *gocql.Session) store.Store {
 		"cql",
 		&breadcrumbStore{session: session},
 		&featureStore{session: session},
+		&segmentStore{session: session},
+		&usageStore{session: session},
 	)
 }
@@ -0,0 +1,97 @@
+package cql
+
+import (
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gocql/gocql"
+	"github.com/robzienert/lever/model"
+)
+
+// usageStore keeps counts in a counter table, and last evaluation times in a
+// separate table, as counter tables cannot have other columns. Last evaluation
+// times are written with their own time as the write timestamp, so that the
+// latest one wins when several servers flush the same usage.
+type usageStore struct {
+	session *gocql.Session
+}
+
+type usageKey struct {
+	namespace string
+	key       string
+	clientID  string
+	outcome   string
+}
+
+func (s *usageStore) GetList() ([]*model.Usage, error) {
+	all := make(map[usageKey]*model.Usage)
+	var (
+		key, namespace, clientID, outcome string
+		count                             int64
+		lastEvaluated                     time.Time
+	)
+
+	query := "SELECT key, namespace, client_id, outcome, count FROM usage_counts"
+	iter := s.session.Query(query).Iter()
+	for iter.Scan(&key, &namespace, &clientID, &outcome, &count) {
+		all[usageKey{namespace, key, clientID, outcome}] = &model.Usage{
+			Namespace: namespace,
+			Key:       key,
+			ClientID:  clientID,
+			Outcome:   outcome,
+			Count:     count,
+		}
+	}
+	if err := iter.Close(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err": err,
+			"q":   query,
+		}).Error("Could not execute CQL query")
+		return nil, err
+	}
+
+	query = "SELECT key, namespace, client_id, outcome, last_evaluated FROM usage_last_evaluated"
+	iter = s.session.Query(query).Iter()
+	for iter.Scan(&key, &namespace, &clientID, &outcome, &lastEvaluated) {
+		if u, ok := all[usageKey{namespace, key, clientID, outcome}]; ok {
+			u.LastEvaluated = lastEvaluated
+		}
+	}
+	if err := iter.Close(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"err": err,
+			"q":   query,
+		}).Error("Could not execute CQL query")
+		return nil, err
+	}
+
+	list := make([]*model.Usage, 0, len(all))
+	for _, u := range all {
+		list = append(list, u)
+	}
+	return list, nil
+}
+
+// Add writes the usage one at a time, as counter updates cannot be batched with
+// other tables. The last evaluation time is written first: writing it again is
+// harmless, while the counter is only incremented once the time is stored, so
+// the usage that failed and everything after it can be retried.
+func (s *usageStore) Add(usage []*model.Usage) ([]*model.Usage, error) {
+	for i, u := range usage {
+		err := s.session.Query(
+			`UPDATE usage_last_evaluated USING TIMESTAMP ? SET last_evaluated = ? WHERE key = ? AND namespace = ? AND client_id = ? AND outcome = ?`,
+			u.LastEvaluated.UnixNano()/int64(time.Microsecond), u.LastEvaluated, u.Key, u.Namespace, u.ClientID, u.Outcome,
+		).Exec()
+		if err != nil {
+			return usage[i:], err
+		}
+		err = s.session.Query(
+			`UPDATE usage_counts SET count = count + ? WHERE key = ? AND namespace = ? AND client_id = ? AND outcome = ?`,
+			u.Count, u.Key, u.Namespace, u.ClientID, u.Outcome,
+		).Exec()
+		if err != nil {
+			return usage[i:], err
+		}
+	}
+	return nil, nil
+}
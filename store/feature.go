@@ -1,16 +1,32 @@
 package store
 
 import (
+	"errors"
+
 	"github.com/robzienert/lever/model"
 	"golang.org/x/net/context"
 )
 
+// ErrConflict is returned when saving or deleting a feature whose revision is
+// not the stored revision, because it was changed since it was read.
+var ErrConflict = errors.New("feature was changed since it was read")
+
+// ErrReadOnly is returned when saving or deleting a feature in a store whose
+// features are managed outside of the service.
+var ErrReadOnly = errors.New("features are read-only in this store")
+
 // FeatureStore is the repository for interacting with the feature backends.
+//
+// Upsert only saves a feature if its revision is the stored revision, or 0 for
+// new features, and increments the feature's revision. Delete only deletes a
+// feature at the stored revision. Both return ErrConflict otherwise, or
+// ErrReadOnly if the store's features cannot be changed at all.
 type FeatureStore interface {
 	Get(string) (*model.Feature, error)
 	GetByNamespace(string, string) (*model.Feature, error)
 	GetList() ([]*model.Feature, error)
 	GetListByNamespace(string) ([]*model.Feature, error)
+	GetAll() ([]*model.Feature, error)
 	Upsert(*model.Feature) error
 	Delete(*model.Feature) error
 }
@@ -33,6 +49,12 @@ func GetFeatureList(c context.Context, namespace string) ([]*model.Feature, erro
 	return FromContext(c).Features().GetListByNamespace(namespace)
 }
 
+// GetAllFeatures will proxy to the net.Context's feature storage backend to
+// get every feature, with or without a namespace.
+func GetAllFeatures(c context.Context) ([]*model.Feature, error) {
+	return FromContext(c).Features().GetAll()
+}
+
 // UpsertFeature will proxy the net.Context's feature storage to save a feature.
 func UpsertFeature(c context.Context, feature *model.Feature) error {
 	return FromContext(c).Features().Upsert(feature)
@@ -0,0 +1,83 @@
+package file
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+)
+
+// featureStore serves the features last loaded from the directory. Reloads
+// replace all features at once, so reads never see a partially loaded
+// directory.
+type featureStore struct {
+	features map[string]*model.Feature
+	lock     sync.RWMutex
+}
+
+func (s *featureStore) Get(key string) (*model.Feature, error) {
+	return s.GetByNamespace("", key)
+}
+
+func (s *featureStore) GetByNamespace(namespace string, key string) (*model.Feature, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	f := s.features[(&model.Feature{Namespace: namespace, Key: key}).ID()]
+	if f == nil {
+		return nil, nil
+	}
+	return f.Copy(), nil
+}
+
+func (s *featureStore) GetList() ([]*model.Feature, error) {
+	return s.GetListByNamespace("")
+}
+
+func (s *featureStore) GetListByNamespace(namespace string) ([]*model.Feature, error) {
+	all, _ := s.GetAll()
+	var features []*model.Feature
+	for _, f := range all {
+		if f.Namespace == namespace {
+			features = append(features, f)
+		}
+	}
+	return features, nil
+}
+
+func (s *featureStore) GetAll() ([]*model.Feature, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var features []*model.Feature
+	for _, f := range s.features {
+		features = append(features, f.Copy())
+	}
+	sort.Sort(byID(features))
+	return features, nil
+}
+
+func (s *featureStore) Upsert(feature *model.Feature) error {
+	return store.ErrReadOnly
+}
+
+func (s *featureStore) Delete(feature *model.Feature) error {
+	return store.ErrReadOnly
+}
+
+func (s *featureStore) snapshot() map[string]*model.Feature {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.features
+}
+
+func (s *featureStore) swap(features map[string]*model.Feature) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.features = features
+}
+
+type byID []*model.Feature
+
+func (f byID) Len() int           { return len(f) }
+func (f byID) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f byID) Less(i, j int) bool { return f[i].ID() < f[j].ID() }
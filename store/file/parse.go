@@ -0,0 +1,164 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/robzienert/lever/model"
+	"gopkg.in/yaml.v2"
+)
+
+// namespaceFile is the contents of a single file, which holds every feature of
+// one namespace. Files without a namespace hold the features that have none.
+type namespaceFile struct {
+	Namespace string           `json:"namespace"`
+	Features  []*model.Feature `json:"features"`
+}
+
+// readDir loads and validates the features of every YAML or JSON file in the
+// directory, by ID. Hidden files and subdirectories are ignored. Features are
+// last updated when their file was modified.
+func readDir(dir string) (map[string]*model.Feature, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	features := make(map[string]*model.Feature)
+	namespaces := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		ext := filepath.Ext(name)
+		if ext != ".yml" && ext != ".yaml" && ext != ".json" {
+			continue
+		}
+
+		f, err := readFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		if other, ok := namespaces[f.Namespace]; ok {
+			return nil, fmt.Errorf("%s: namespace %q is already defined in %s", name, f.Namespace, other)
+		}
+		namespaces[f.Namespace] = name
+
+		for _, feature := range f.Features {
+			if err := validate(f.Namespace, feature); err != nil {
+				return nil, fmt.Errorf("%s: %s", name, err)
+			}
+			if _, ok := features[feature.ID()]; ok {
+				return nil, fmt.Errorf("%s: feature %s is defined more than once", name, feature.ID())
+			}
+			feature.LastUpdated = entry.ModTime().UTC()
+			features[feature.ID()] = feature
+		}
+	}
+
+	lookup := func(namespace string, key string) (*model.Feature, error) {
+		return features[(&model.Feature{Namespace: namespace, Key: key}).ID()], nil
+	}
+	for _, feature := range features {
+		for _, p := range feature.Prerequisites {
+			if features[p.ID()] == nil {
+				return nil, fmt.Errorf("%s: prerequisite feature does not exist: %s", feature.ID(), p.ID())
+			}
+		}
+		cycle, _ := feature.PrerequisiteCycle(lookup)
+		if cycle != nil {
+			return nil, fmt.Errorf("prerequisite cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	return features, nil
+}
+
+// readFile decodes a namespace file. YAML is converted to JSON first, so that
+// features are decoded by their JSON field names in both formats. Unknown
+// fields are rejected, since they are most likely typos.
+func readFile(path string) (*namespaceFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) != ".json" {
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		v, err = jsonValue(v)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	f := &namespaceFile{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// jsonValue converts the maps decoded from YAML, which can have keys of any
+// type, to maps that can be encoded as JSON.
+func jsonValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("non-string key: %v", k)
+			}
+			value, err := jsonValue(value)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	case []interface{}:
+		for i, value := range v {
+			value, err := jsonValue(value)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = value
+		}
+	}
+	return v, nil
+}
+
+// validate checks a feature the way it would be checked when saved through the
+// API, and places it in the file's namespace. Features keep the default
+// bucketing seed of features created through the API.
+func validate(namespace string, f *model.Feature) error {
+	if f.Namespace != "" && f.Namespace != namespace {
+		return fmt.Errorf("feature %s is not in the file's namespace", f.ID())
+	}
+	f.Namespace = namespace
+	if f.Key == "" || f.Type == "" || f.Value == "" || f.Gate == nil {
+		return fmt.Errorf("feature %s requires a key, type, value and gate", f.ID())
+	}
+	// Rollout plans advance by saving the feature, which files do not allow.
+	if f.Rollout != nil {
+		return fmt.Errorf("feature %s: rollout plans are not supported in files", f.ID())
+	}
+	if err := f.Validate(); err != nil {
+		return fmt.Errorf("feature %s: %s", f.ID(), err)
+	}
+	if f.Gate.Seed == "" {
+		f.Gate.Seed = f.BucketSeed()
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package file
+
+import (
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+)
+
+// segmentStore serves the segments of the other store, but rejects changes to
+// them: segments cannot be defined in files, and saving them in the other store
+// would not be durable.
+type segmentStore struct {
+	store.SegmentStore
+}
+
+func (s *segmentStore) Upsert(segment *model.Segment) error {
+	return store.ErrReadOnly
+}
+
+func (s *segmentStore) Delete(segment *model.Segment) error {
+	return store.ErrReadOnly
+}
@@ -0,0 +1,53 @@
+package file
+
+import (
+	"github.com/DataDog/datadog-go/statsd"
+	"github.com/robzienert/http-healthcheck"
+	"github.com/robzienert/lever/store"
+)
+
+// StoreResponse encapsulates all resulting objects from a file Store load.
+//
+// The watcher is returned so that it can be started, and its Close deferred,
+// from the main method.
+type StoreResponse struct {
+	Store          store.Store
+	Watcher        *Watcher
+	HealthProvider healthcheck.Provider
+}
+
+// Load a new read-only storage backend from the feature files in dir. The
+// breadcrumbs and usage are kept in the other store, and its segments are
+// served read-only. Only the features are durable: the breadcrumbs and usage
+// are as durable as the other store, which is in memory when run from main.
+//
+// Loading fails if the files are not valid, but once loaded, files that become
+// invalid only make the store unhealthy.
+func Load(dir string, other store.Store, statsd *statsd.Client) (*StoreResponse, error) {
+	features := &featureStore{}
+	s := store.New("file", other.Breadcrumbs(), features, &segmentStore{other.Segments()}, other.Usage())
+
+	watcher, err := newWatcher(dir, s, features, statsd)
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Reload(); err != nil {
+		watcher.watcher.Close()
+		return nil, err
+	}
+
+	return &StoreResponse{
+		Store:          s,
+		Watcher:        watcher,
+		HealthProvider: &healthProvider{watcher: watcher},
+	}, nil
+}
+
+type healthProvider struct {
+	watcher *Watcher
+}
+
+func (p *healthProvider) Name() string     { return "file" }
+func (p *healthProvider) Start() error     { return nil }
+func (p *healthProvider) Close() error     { return nil }
+func (p *healthProvider) IsHealthy() error { return p.watcher.Err() }
@@ -0,0 +1,236 @@
+package file
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+const apiFeatures = `
+namespace: api
+features:
+- key: search
+  type: java.lang.Boolean
+  value: "true"
+  gate:
+    value: "true"
+- key: ranking
+  type: java.lang.Boolean
+  value: "true"
+  gate:
+    actorPercent: 10
+  prerequisites:
+  - namespace: api
+    key: search
+`
+
+const globalFeatures = `{
+  "features": [
+    {"key": "maintenance", "type": "java.lang.Boolean", "value": "true", "gate": {"value": "false"}}
+  ]
+}`
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, data := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+}
+
+func touch(t *testing.T, dir string, name string, modified time.Time) {
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, name), modified, modified))
+}
+
+func tempDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "lever-file")
+	assert.NoError(t, err)
+	writeFiles(t, dir, files)
+	return dir
+}
+
+func TestReadDir(t *testing.T) {
+	dir := tempDir(t, map[string]string{
+		"api.yml":      apiFeatures,
+		"global.json":  globalFeatures,
+		".api.yml.swp": "not yaml: [",
+		"README.md":    "# Features",
+	})
+	defer os.RemoveAll(dir)
+
+	features, err := readDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, features, 3)
+	if f := features["api/ranking"]; assert.NotNil(t, f) {
+		assert.Equal(t, "api", f.Namespace)
+		assert.Equal(t, 10, f.Gate.ActorPercent)
+		assert.Equal(t, "api/ranking", f.Gate.Seed)
+	}
+	if f := features["maintenance"]; assert.NotNil(t, f) {
+		assert.Equal(t, "", f.Namespace)
+		assert.Equal(t, "false", f.Gate.Value)
+	}
+}
+
+func TestReadDir_Invalid(t *testing.T) {
+	cases := []map[string]string{
+		{"api.yml": "namespace: api\nfeatures:\n- key: a\n  type: java.lang.Boolean\n  value: \"true\"\n  gate: {}\n  gates: {}\n"},
+		{"api.yml": "namespace: api\nfeatures:\n- key: a\n  namespace: web\n  type: java.lang.Boolean\n  value: \"true\"\n  gate: {}\n"},
+		{"api.yml": "namespace: api\nfeatures:\n- key: a\n  type: java.lang.Boolean\n  value: \"true\"\n"},
+		{"api.yml": "namespace: api\nfeatures:\n- key: a\n  type: java.lang.Boolean\n  value: \"true\"\n  gate: {}\n  lifecycle: {kind: forever}\n"},
+		{"api.yml": "namespace: api\nfeatures:\n- key: a\n  type: java.lang.Boolean\n  value: \"true\"\n  gate: {}\n  rollout: {steps: [10, 100], interval: 1h}\n"},
+		{"api.yml": "namespace: api\nfeatures:\n- key: a\n  type: java.lang.Boolean\n  value: \"true\"\n  gate: {}\n  prerequisites: [{key: b}]\n"},
+		{"api.yml": apiFeatures, "api2.yaml": "namespace: api\n"},
+		{"api.yml": apiFeatures + "- key: search\n  type: java.lang.Boolean\n  value: \"true\"\n  gate: {}\n"},
+		{"api.yml": "namespace: [api"},
+	}
+	for i, files := range cases {
+		dir := tempDir(t, files)
+		_, err := readDir(dir)
+		assert.Error(t, err, fmt.Sprintf("case %d", i+1))
+		os.RemoveAll(dir)
+	}
+}
+
+func TestStore_ReadOnly(t *testing.T) {
+	dir := tempDir(t, map[string]string{"api.yml": apiFeatures})
+	defer os.RemoveAll(dir)
+	modified := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	touch(t, dir, "api.yml", modified)
+	resp, err := Load(dir, memory.Load(), nil)
+	assert.NoError(t, err)
+	defer resp.Watcher.watcher.Close()
+
+	f, err := resp.Store.Features().GetByNamespace("api", "search")
+	assert.NoError(t, err)
+	assert.Equal(t, modified.UnixNano(), f.Revision)
+	assert.Equal(t, modified, f.DateCreated)
+	assert.Equal(t, modified, f.LastUpdated)
+	assert.Equal(t, store.ErrReadOnly, resp.Store.Features().Upsert(f))
+	assert.Equal(t, store.ErrReadOnly, resp.Store.Features().Delete(f))
+
+	list, err := resp.Store.Features().GetListByNamespace("api")
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, "ranking", list[0].Key)
+	}
+	list, err = resp.Store.Features().GetList()
+	assert.NoError(t, err)
+	assert.Len(t, list, 0)
+
+	segment := &model.Segment{Name: "beta", Actors: []string{"one"}}
+	assert.Equal(t, store.ErrReadOnly, resp.Store.Segments().Upsert(segment))
+	assert.Equal(t, store.ErrReadOnly, resp.Store.Segments().Delete(segment))
+	segments, err := resp.Store.Segments().GetList()
+	assert.NoError(t, err)
+	assert.Len(t, segments, 0)
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	dir := tempDir(t, map[string]string{"api.yml": apiFeatures, "global.json": globalFeatures})
+	defer os.RemoveAll(dir)
+	modified := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	touch(t, dir, "api.yml", modified)
+	memStore := memory.Load()
+	resp, err := Load(dir, memStore, nil)
+	assert.NoError(t, err)
+	w := resp.Watcher
+	defer w.watcher.Close()
+	created, _ := resp.Store.Features().GetByNamespace("api", "search")
+
+	breadcrumbs, _ := memStore.Breadcrumbs().GetList()
+	if assert.Len(t, breadcrumbs, 1) {
+		assert.Equal(t, model.ReloadFeaturesAction, breadcrumbs[0].Action)
+		assert.Equal(t, "created", breadcrumbs[0].Fields["api/search"])
+		assert.Len(t, breadcrumbs[0].Fields, 3)
+	}
+
+	// Reloading unchanged files changes nothing, even if they were modified.
+	touch(t, dir, "api.yml", modified.Add(time.Minute))
+	assert.NoError(t, w.Reload())
+	breadcrumbs, _ = memStore.Breadcrumbs().GetList()
+	assert.Len(t, breadcrumbs, 1)
+	f, _ := resp.Store.Features().GetByNamespace("api", "search")
+	assert.Equal(t, created, f, "unchanged feature was updated")
+
+	writeFiles(t, dir, map[string]string{"global.json": `{"features": []}`})
+	assert.NoError(t, w.Reload())
+	breadcrumbs, _ = memStore.Breadcrumbs().GetList()
+	if assert.Len(t, breadcrumbs, 2) {
+		assert.Equal(t, model.Fields{"maintenance": "deleted"}, breadcrumbs[1].Fields)
+	}
+
+	withoutPrerequisites := apiFeatures[:len(apiFeatures)-len("  prerequisites:\n  - namespace: api\n    key: search\n")] + "  prerequisites: []\n"
+	writeFiles(t, dir, map[string]string{"api.yml": withoutPrerequisites})
+	touch(t, dir, "api.yml", modified.Add(time.Hour))
+	assert.NoError(t, w.Reload())
+	f, _ = resp.Store.Features().GetByNamespace("api", "ranking")
+	assert.Equal(t, modified.Add(time.Hour).UnixNano(), f.Revision)
+	assert.Equal(t, modified, f.DateCreated)
+	assert.Equal(t, modified.Add(time.Hour), f.LastUpdated)
+	f, _ = resp.Store.Features().GetByNamespace("api", "search")
+	assert.Equal(t, created, f, "unchanged feature was updated")
+	breadcrumbs, _ = memStore.Breadcrumbs().GetList()
+	if assert.Len(t, breadcrumbs, 3) {
+		assert.Equal(t, model.Fields{"api/ranking.prerequisites": "api/search=true -> NO_VALUE"}, breadcrumbs[2].Fields)
+	}
+
+	// Invalid files keep the loaded features, but make the store unhealthy.
+	writeFiles(t, dir, map[string]string{"api.yml": "namespace: [api"})
+	assert.Error(t, w.Reload())
+	assert.Error(t, resp.HealthProvider.IsHealthy())
+	f, _ = resp.Store.Features().GetByNamespace("api", "ranking")
+	assert.NotNil(t, f)
+
+	// Changes keep moving the revision forward when the file is modified at an
+	// earlier time.
+	writeFiles(t, dir, map[string]string{"api.yml": apiFeatures})
+	touch(t, dir, "api.yml", modified)
+	assert.NoError(t, w.Reload())
+	assert.NoError(t, resp.HealthProvider.IsHealthy())
+	f, _ = resp.Store.Features().GetByNamespace("api", "ranking")
+	assert.Equal(t, modified.Add(time.Hour).UnixNano()+1, f.Revision)
+}
+
+func TestWatcher_ReloadRestart(t *testing.T) {
+	dir := tempDir(t, map[string]string{"api.yml": apiFeatures})
+	defer os.RemoveAll(dir)
+	touch(t, dir, "api.yml", time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+
+	// Another server, or the same one after a restart, serves the same
+	// revisions and dates for the same files.
+	var loaded []*model.Feature
+	for i := 0; i < 2; i++ {
+		resp, err := Load(dir, memory.Load(), nil)
+		assert.NoError(t, err)
+		f, _ := resp.Store.Features().GetByNamespace("api", "ranking")
+		loaded = append(loaded, f)
+		resp.Watcher.watcher.Close()
+	}
+	assert.Equal(t, loaded[0], loaded[1])
+}
+
+func TestWatcher_Start(t *testing.T) {
+	dir := tempDir(t, map[string]string{"api.yml": apiFeatures})
+	defer os.RemoveAll(dir)
+	resp, err := Load(dir, memory.Load(), nil)
+	assert.NoError(t, err)
+	resp.Watcher.Start()
+	defer resp.Watcher.Close()
+
+	writeFiles(t, dir, map[string]string{"global.json": globalFeatures})
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if f, _ := resp.Store.Features().Get("maintenance"); f != nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("new feature file was not loaded")
+}
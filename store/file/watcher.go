@@ -0,0 +1,163 @@
+package file
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+	"github.com/Sirupsen/logrus"
+	"github.com/fsnotify/fsnotify"
+	"github.com/robzienert/lever/metrics"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+	"golang.org/x/net/context"
+)
+
+// reloadDelay is how long to wait for more changes before reloading, so that
+// a checkout changing many files only reloads once.
+const reloadDelay = 100 * time.Millisecond
+
+// Watcher reloads the features whenever the directory changes.
+//
+// Every change to the directory reloads all of its files. When any of them is
+// not valid, the previously loaded features are kept, and the store reports
+// itself as unhealthy until the directory is fixed.
+type Watcher struct {
+	ctx      context.Context
+	dir      string
+	features *featureStore
+	watcher  *fsnotify.Watcher
+	done     chan struct{}
+	stopped  chan struct{}
+
+	lock sync.Mutex
+	err  error
+}
+
+func newWatcher(dir string, s store.Store, features *featureStore, statsd *statsd.Client) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	ctx := context.WithValue(context.Background(), store.Key, s)
+	if statsd != nil {
+		ctx = context.WithValue(ctx, metrics.Key, statsd)
+	}
+	return &Watcher{
+		ctx:      ctx,
+		dir:      dir,
+		features: features,
+		watcher:  watcher,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}, nil
+}
+
+// Start reloads the features in the background whenever the directory
+// changes, until the watcher is closed.
+func (w *Watcher) Start() {
+	go func() {
+		defer close(w.stopped)
+		var reload <-chan time.Time
+		for {
+			select {
+			case <-w.watcher.Events:
+				reload = time.After(reloadDelay)
+			case err := <-w.watcher.Errors:
+				logrus.WithField("err", err).Error("Error watching feature files")
+			case <-reload:
+				reload = nil
+				if err := w.Reload(); err != nil {
+					logrus.WithField("err", err).Error("Could not reload feature files")
+				}
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the watcher and waits for a running reload to finish.
+func (w *Watcher) Close() {
+	close(w.done)
+	<-w.stopped
+	w.watcher.Close()
+}
+
+// Err returns the error of the last reload, or nil if it succeeded.
+func (w *Watcher) Err() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.err
+}
+
+// Reload loads all files of the directory and replaces the served features
+// with them, if they are all valid. A breadcrumb with the diff of every changed
+// feature is saved when anything changed.
+//
+// Features are last updated when their file was modified, and their revision
+// is that time in nanoseconds, so that both stay the same across restarts.
+// Unchanged features keep their revision and dates even if their file was
+// modified, and features first loaded since the server started are created
+// when their file was modified.
+func (w *Watcher) Reload() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	features, err := readDir(w.dir)
+	w.err = err
+	if err != nil {
+		return err
+	}
+
+	previous := w.features.snapshot()
+	fields := model.Fields{}
+	for id, f := range features {
+		old, ok := previous[id]
+		if !ok {
+			f.Revision = f.LastUpdated.UnixNano()
+			f.DateCreated = f.LastUpdated
+			fields[id] = "created"
+			continue
+		}
+		diff := old.Diff(f)
+		if len(diff) == 0 {
+			f.Revision = old.Revision
+			f.DateCreated = old.DateCreated
+			f.LastUpdated = old.LastUpdated
+			continue
+		}
+		// A file can be modified at an earlier time, such as when its clock
+		// is behind, but the revision must still move forward.
+		f.Revision = f.LastUpdated.UnixNano()
+		if f.Revision <= old.Revision {
+			f.Revision = old.Revision + 1
+		}
+		f.DateCreated = old.DateCreated
+		for k, v := range diff {
+			fields[id+"."+k] = v
+		}
+	}
+	for id := range previous {
+		if _, ok := features[id]; !ok {
+			fields[id] = "deleted"
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	w.features.swap(features)
+	logrus.WithField("dir", w.dir).WithField("features", len(features)).Info("Reloaded feature files")
+
+	b := model.NewBreadcrumb(model.ReloadFeaturesAction, model.AutomatedActor).WithFields(fields)
+	if err := store.SaveBreadcrumb(w.ctx, b); err != nil {
+		logrus.WithField("err", err).Error("Could not save breadcrumb for reloaded feature files")
+	}
+	return nil
+}
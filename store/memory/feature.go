@@ -4,33 +4,30 @@ import (
 	"sync"
 
 	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
 )
 
+// featureStore keeps copies of the features it is given and returns copies, so
+// that features can only be changed through Upsert.
 type featureStore struct {
 	features []*model.Feature
 	lock     sync.RWMutex
 }
 
 func (s *featureStore) Get(key string) (*model.Feature, error) {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
 	return s.GetByNamespace("", key)
 }
 
 func (s *featureStore) GetByNamespace(namespace string, key string) (*model.Feature, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
-	for _, f := range s.features {
-		if f.Namespace == namespace && f.Key == key {
-			return f, nil
-		}
+	if i := s.index(namespace, key); i >= 0 {
+		return s.features[i].Copy(), nil
 	}
 	return nil, nil
 }
 
 func (s *featureStore) GetList() ([]*model.Feature, error) {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
 	return s.GetListByNamespace("")
 }
 
@@ -40,32 +37,65 @@ func (s *featureStore) GetListByNamespace(namespace string) ([]*model.Feature, e
 	var features []*model.Feature
 	for _, f := range s.features {
 		if f.Namespace == namespace {
-			features = append(features, f)
+			features = append(features, f.Copy())
 		}
 	}
 	return features, nil
 }
 
+func (s *featureStore) GetAll() ([]*model.Feature, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var features []*model.Feature
+	for _, f := range s.features {
+		features = append(features, f.Copy())
+	}
+	return features, nil
+}
+
 func (s *featureStore) Upsert(feature *model.Feature) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	for i, f := range s.features {
-		if f.Namespace == feature.Namespace && f.Key == feature.Key {
-			s.features[i] = feature
-			return nil
-		}
+	i := s.index(feature.Namespace, feature.Key)
+	var stored int64
+	if i >= 0 {
+		stored = s.features[i].Revision
+	}
+	if feature.Revision != stored {
+		return store.ErrConflict
+	}
+
+	feature.Revision++
+	if i >= 0 {
+		s.features[i] = feature.Copy()
+	} else {
+		s.features = append(s.features, feature.Copy())
 	}
-	s.features = append(s.features, feature)
 	return nil
 }
 
 func (s *featureStore) Delete(feature *model.Feature) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	for i, f := range s.features {
-		if f.Namespace == feature.Namespace && f.Key == feature.Key {
-			s.features = append(s.features[:i], s.features[i+1:]...)
+	i := s.index(feature.Namespace, feature.Key)
+	if i < 0 {
+		if feature.Revision != 0 {
+			return store.ErrConflict
 		}
+		return nil
 	}
+	if s.features[i].Revision != feature.Revision {
+		return store.ErrConflict
+	}
+	s.features = append(s.features[:i], s.features[i+1:]...)
 	return nil
 }
+
+func (s *featureStore) index(namespace string, key string) int {
+	for i, f := range s.features {
+		if f.Namespace == namespace && f.Key == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/robzienert/lever/model"
+)
+
+type segmentStore struct {
+	segments []*model.Segment
+	lock     sync.RWMutex
+}
+
+func (s *segmentStore) Get(name string) (*model.Segment, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, segment := range s.segments {
+		if segment.Name == name {
+			return segment.Copy(), nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *segmentStore) GetList() ([]*model.Segment, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	segments := make([]*model.Segment, len(s.segments))
+	for i, segment := range s.segments {
+		segments[i] = segment.Copy()
+	}
+	return segments, nil
+}
+
+func (s *segmentStore) Upsert(segment *model.Segment) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, existing := range s.segments {
+		if existing.Name == segment.Name {
+			s.segments[i] = segment.Copy()
+			return nil
+		}
+	}
+	s.segments = append(s.segments, segment.Copy())
+	return nil
+}
+
+func (s *segmentStore) Delete(segment *model.Segment) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, existing := range s.segments {
+		if existing.Name == segment.Name {
+			s.segments = append(s.segments[:i], s.segments[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
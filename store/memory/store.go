@@ -13,5 +13,7 @@ func New() store.Store {
 		"memory",
 		&breadcrumbStore{},
 		&featureStore{},
+		&segmentStore{},
+		&usageStore{},
 	)
 }
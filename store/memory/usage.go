@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/robzienert/lever/model"
+)
+
+type usageKey struct {
+	namespace string
+	key       string
+	clientID  string
+	outcome   string
+}
+
+type usageStore struct {
+	usage map[usageKey]*model.Usage
+	lock  sync.RWMutex
+}
+
+func (s *usageStore) GetList() ([]*model.Usage, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var all []*model.Usage
+	for _, u := range s.usage {
+		c := *u
+		all = append(all, &c)
+	}
+	return all, nil
+}
+
+func (s *usageStore) Add(usage []*model.Usage) ([]*model.Usage, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.usage == nil {
+		s.usage = make(map[usageKey]*model.Usage)
+	}
+	for _, u := range usage {
+		k := usageKey{u.Namespace, u.Key, u.ClientID, u.Outcome}
+		if existing, ok := s.usage[k]; ok {
+			existing.Add(u)
+			continue
+		}
+		c := *u
+		s.usage[k] = &c
+	}
+	return nil, nil
+}
@@ -5,6 +5,7 @@ import "github.com/robzienert/lever/model"
 type FeatureStore struct {
 	GetFn     func(namespace string, key string) (*model.Feature, error)
 	GetListFn func(namespace string) ([]*model.Feature, error)
+	GetAllFn  func() ([]*model.Feature, error)
 	UpsertFn  func(feature *model.Feature) error
 	DeleteFn  func(feature *model.Feature) error
 }
@@ -25,6 +26,10 @@ func (s *FeatureStore) GetListByNamespace(namespace string) ([]*model.Feature, e
 	return s.GetListFn(namespace)
 }
 
+func (s *FeatureStore) GetAll() ([]*model.Feature, error) {
+	return s.GetAllFn()
+}
+
 func (s *FeatureStore) Upsert(feature *model.Feature) error {
 	return s.UpsertFn(feature)
 }
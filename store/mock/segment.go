@@ -0,0 +1,26 @@
+package mock
+
+import "github.com/robzienert/lever/model"
+
+type SegmentStore struct {
+	GetFn     func(name string) (*model.Segment, error)
+	GetListFn func() ([]*model.Segment, error)
+	UpsertFn  func(segment *model.Segment) error
+	DeleteFn  func(segment *model.Segment) error
+}
+
+func (s *SegmentStore) Get(name string) (*model.Segment, error) {
+	return s.GetFn(name)
+}
+
+func (s *SegmentStore) GetList() ([]*model.Segment, error) {
+	return s.GetListFn()
+}
+
+func (s *SegmentStore) Upsert(segment *model.Segment) error {
+	return s.UpsertFn(segment)
+}
+
+func (s *SegmentStore) Delete(segment *model.Segment) error {
+	return s.DeleteFn(segment)
+}
@@ -3,5 +3,9 @@ package mock
 import "github.com/robzienert/lever/store"
 
 func LoadFeatureStore(featureStore *FeatureStore) store.Store {
-	return store.New("mock", &BreadcrumbStore{}, featureStore)
+	return store.New("mock", &BreadcrumbStore{}, featureStore, &SegmentStore{}, &UsageStore{})
+}
+
+func LoadSegmentStore(segmentStore *SegmentStore) store.Store {
+	return store.New("mock", &BreadcrumbStore{}, &FeatureStore{}, segmentStore, &UsageStore{})
 }
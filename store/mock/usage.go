@@ -0,0 +1,13 @@
+package mock
+
+import "github.com/robzienert/lever/model"
+
+type UsageStore struct{}
+
+func (s *UsageStore) GetList() ([]*model.Usage, error) {
+	return nil, nil
+}
+
+func (s *UsageStore) Add(usage []*model.Usage) ([]*model.Usage, error) {
+	return nil, nil
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"github.com/robzienert/lever/model"
+	"golang.org/x/net/context"
+)
+
+// SegmentStore is the repository for interacting with the segment backends.
+type SegmentStore interface {
+	Get(string) (*model.Segment, error)
+	GetList() ([]*model.Segment, error)
+	Upsert(*model.Segment) error
+	Delete(*model.Segment) error
+}
+
+// GetSegment will proxy to the net.Context's segment storage backend to get
+// an individual segment by name.
+func GetSegment(c context.Context, name string) (*model.Segment, error) {
+	return FromContext(c).Segments().Get(name)
+}
+
+// GetSegmentList will proxy to the net.Context's segment storage backend to
+// get all segments.
+func GetSegmentList(c context.Context) ([]*model.Segment, error) {
+	return FromContext(c).Segments().GetList()
+}
+
+// UpsertSegment will proxy the net.Context's segment storage to save a segment.
+func UpsertSegment(c context.Context, segment *model.Segment) error {
+	return FromContext(c).Segments().Upsert(segment)
+}
+
+// DeleteSegment will proxy the net.Context's segment storage to delete
+// segments.
+func DeleteSegment(c context.Context, segment *model.Segment) error {
+	return FromContext(c).Segments().Delete(segment)
+}
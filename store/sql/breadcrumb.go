@@ -0,0 +1,49 @@
+package sql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/robzienert/lever/model"
+)
+
+type breadcrumbStore struct {
+	db      *sql.DB
+	dialect *Dialect
+}
+
+func (s *breadcrumbStore) GetList() ([]*model.Breadcrumb, error) {
+	rows, err := s.db.Query("SELECT action, actor, fields, date_created FROM breadcrumbs ORDER BY date_created")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breadcrumbs []*model.Breadcrumb
+	for rows.Next() {
+		b := &model.Breadcrumb{}
+		var fields string
+		var created time.Time
+		if err := rows.Scan(&b.Action, &b.Actor, &fields, &created); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(fields), &b.Fields); err != nil {
+			return nil, err
+		}
+		b.DateCreated = created.UTC()
+		breadcrumbs = append(breadcrumbs, b)
+	}
+	return breadcrumbs, rows.Err()
+}
+
+func (s *breadcrumbStore) Create(b *model.Breadcrumb) error {
+	fields, err := jsonColumn(b.Fields)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.dialect.rebind(
+		"INSERT INTO breadcrumbs (action, actor, fields, date_created) VALUES (?, ?, ?, ?)"),
+		b.Action, b.Actor, fields, b.DateCreated.UTC())
+	return err
+}
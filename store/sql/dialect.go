@@ -0,0 +1,66 @@
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	// Registers the drivers of the supported dialects.
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Dialect describes how a SQL database differs from the queries of the store,
+// which are written with "?" placeholders and use only syntax that both
+// PostgreSQL (9.5+) and SQLite (3.24+) understand.
+type Dialect struct {
+	// Name is also the name of the database/sql driver.
+	Name string
+	// MaxOpenConns limits the connections to the database, or 0 for no limit.
+	MaxOpenConns int
+
+	placeholder func(n int) string
+}
+
+// Postgres is the dialect of PostgreSQL, using the lib/pq driver.
+var Postgres = &Dialect{
+	Name:        "postgres",
+	placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+}
+
+// SQLite is the dialect of SQLite, using the go-sqlite3 driver. SQLite only
+// allows one writer at a time, and every connection to an in-memory database
+// opens a different database, so it uses a single connection.
+var SQLite = &Dialect{
+	Name:         "sqlite3",
+	MaxOpenConns: 1,
+	placeholder:  func(int) string { return "?" },
+}
+
+var dialects = []*Dialect{Postgres, SQLite}
+
+// GetDialect returns the dialect with the given name.
+func GetDialect(name string) (*Dialect, error) {
+	for _, d := range dialects {
+		if d.Name == name {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown sql dialect: %s", name)
+}
+
+// rebind replaces the "?" placeholders of the query with the dialect's.
+func (d *Dialect) rebind(query string) string {
+	parts := strings.Split(query, "?")
+	if len(parts) == 1 {
+		return query
+	}
+	var b bytes.Buffer
+	b.WriteString(parts[0])
+	for i, part := range parts[1:] {
+		b.WriteString(d.placeholder(i + 1))
+		b.WriteString(part)
+	}
+	return b.String()
+}
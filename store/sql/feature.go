@@ -0,0 +1,142 @@
+package sql
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+)
+
+type featureStore struct {
+	db      *sql.DB
+	dialect *Dialect
+}
+
+func (s *featureStore) Get(key string) (*model.Feature, error) {
+	return s.GetByNamespace("", key)
+}
+
+func (s *featureStore) GetByNamespace(namespace string, key string) (*model.Feature, error) {
+	row := s.db.QueryRow(s.dialect.rebind(
+		"SELECT data, revision FROM features WHERE namespace = ? AND key = ?"),
+		namespace, key)
+	f, err := scanFeature(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return f, err
+}
+
+func (s *featureStore) GetList() ([]*model.Feature, error) {
+	return s.GetListByNamespace("")
+}
+
+func (s *featureStore) GetListByNamespace(namespace string) ([]*model.Feature, error) {
+	return s.query("SELECT data, revision FROM features WHERE namespace = ? ORDER BY key", namespace)
+}
+
+func (s *featureStore) GetAll() ([]*model.Feature, error) {
+	return s.query("SELECT data, revision FROM features ORDER BY namespace, key")
+}
+
+func (s *featureStore) Upsert(feature *model.Feature) error {
+	saved := feature.Copy()
+	saved.Revision++
+	data, err := jsonColumn(saved)
+	if err != nil {
+		return err
+	}
+
+	var result sql.Result
+	if feature.Revision == 0 {
+		result, err = s.db.Exec(s.dialect.rebind(`
+			INSERT INTO features (namespace, key, revision, data) VALUES (?, ?, ?, ?)
+			ON CONFLICT (namespace, key) DO NOTHING`),
+			feature.Namespace, feature.Key, saved.Revision, data)
+	} else {
+		result, err = s.db.Exec(s.dialect.rebind(`
+			UPDATE features SET revision = ?, data = ?
+			WHERE namespace = ? AND key = ? AND revision = ?`),
+			saved.Revision, data, feature.Namespace, feature.Key, feature.Revision)
+	}
+	if err := checkApplied(result, err); err != nil {
+		return err
+	}
+	feature.Revision = saved.Revision
+	return nil
+}
+
+func (s *featureStore) Delete(feature *model.Feature) error {
+	result, err := s.db.Exec(s.dialect.rebind(
+		"DELETE FROM features WHERE namespace = ? AND key = ? AND revision = ?"),
+		feature.Namespace, feature.Key, feature.Revision)
+	err = checkApplied(result, err)
+	if err == store.ErrConflict && feature.Revision == 0 {
+		// Deleting a feature that was never saved is a no-op, as long as it
+		// has not been created since.
+		existing, getErr := s.GetByNamespace(feature.Namespace, feature.Key)
+		if getErr != nil {
+			return getErr
+		}
+		if existing == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (s *featureStore) query(query string, args ...interface{}) ([]*model.Feature, error) {
+	rows, err := s.db.Query(s.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var features []*model.Feature
+	for rows.Next() {
+		f, err := scanFeature(rows)
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, f)
+	}
+	return features, rows.Err()
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanFeature reads a feature from its data and revision columns. The revision
+// column is the one conditional writes check, so it takes precedence.
+func scanFeature(row scanner) (*model.Feature, error) {
+	var data string
+	var revision int64
+	if err := row.Scan(&data, &revision); err != nil {
+		return nil, err
+	}
+	f := &model.Feature{}
+	if err := json.Unmarshal([]byte(data), f); err != nil {
+		return nil, err
+	}
+	f.Revision = revision
+	return f, nil
+}
+
+// checkApplied returns store.ErrConflict if a conditional write did not change
+// any rows.
+func checkApplied(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return store.ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package sql
+
+import (
+	"database/sql"
+
+	"github.com/robzienert/http-healthcheck"
+)
+
+type healthProvider struct {
+	db *sql.DB
+}
+
+// NewHealthProvider creates a health provider that is unhealthy while the
+// database cannot be reached.
+func NewHealthProvider(db *sql.DB) healthcheck.Provider {
+	return &healthProvider{db: db}
+}
+
+func (p *healthProvider) Name() string { return "sql" }
+func (p *healthProvider) Start() error { return nil }
+func (p *healthProvider) Close() error { return nil }
+
+func (p *healthProvider) IsHealthy() error {
+	return p.db.Ping()
+}
@@ -0,0 +1,120 @@
+package sql
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Migration is a versioned change to the schema. Migrations are applied in
+// order, each once, and are recorded by name in the schema_migrations table.
+// Applied migrations must never be changed; add a new one instead.
+type Migration struct {
+	Name string
+	Data string
+}
+
+// migrations only use types that PostgreSQL and SQLite both support.
+//
+// Features and segments keep their configuration as JSON in the data column,
+// so that adding fields to the model does not need a migration. Features
+// without a namespace are stored with the empty namespace.
+var migrations = []Migration{
+	{
+		Name: "2026-10-18-initial_release",
+		Data: `
+		CREATE TABLE features (
+			namespace VARCHAR(255) NOT NULL,
+			key VARCHAR(255) NOT NULL,
+			revision BIGINT NOT NULL,
+			data TEXT NOT NULL,
+			PRIMARY KEY(namespace, key)
+		);
+
+		CREATE TABLE breadcrumbs (
+			action VARCHAR(255) NOT NULL,
+			actor VARCHAR(255) NOT NULL,
+			fields TEXT NOT NULL,
+			date_created TIMESTAMP NOT NULL
+		);
+		CREATE INDEX breadcrumbs_date_created ON breadcrumbs (date_created);
+
+		CREATE TABLE segments (
+			name VARCHAR(255) NOT NULL,
+			data TEXT NOT NULL,
+			PRIMARY KEY(name)
+		);
+
+		CREATE TABLE feature_usage (
+			namespace VARCHAR(255) NOT NULL,
+			key VARCHAR(255) NOT NULL,
+			client_id VARCHAR(255) NOT NULL,
+			outcome VARCHAR(255) NOT NULL,
+			count BIGINT NOT NULL,
+			last_evaluated TIMESTAMP NOT NULL,
+			PRIMARY KEY(namespace, key, client_id, outcome)
+		);
+		`,
+	},
+}
+
+// RunMigrations applies the migrations that have not been applied to the
+// database yet, each in its own transaction.
+func RunMigrations(db *sql.DB, d *Dialect, migrations []Migration) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			name VARCHAR(255) NOT NULL,
+			date_applied TIMESTAMP NOT NULL,
+			PRIMARY KEY(name)
+		)`)
+	if err != nil {
+		return err
+	}
+
+	applied := make(map[string]bool)
+	rows, err := db.Query("SELECT name FROM schema_migrations")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		applied[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	for _, m := range migrations {
+		if applied[m.Name] {
+			continue
+		}
+		if err := runMigration(db, d, m); err != nil {
+			return err
+		}
+		logrus.WithField("name", m.Name).Info("Applied SQL migration")
+	}
+	return nil
+}
+
+func runMigration(db *sql.DB, d *Dialect, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(m.Data); err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.Exec(d.rebind("INSERT INTO schema_migrations (name, date_applied) VALUES (?, ?)"), m.Name, time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,68 @@
+package sql
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/robzienert/lever/model"
+)
+
+type segmentStore struct {
+	db      *sql.DB
+	dialect *Dialect
+}
+
+func (s *segmentStore) Get(name string) (*model.Segment, error) {
+	var data string
+	err := s.db.QueryRow(s.dialect.rebind("SELECT data FROM segments WHERE name = ?"), name).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	segment := &model.Segment{}
+	if err := json.Unmarshal([]byte(data), segment); err != nil {
+		return nil, err
+	}
+	return segment, nil
+}
+
+func (s *segmentStore) GetList() ([]*model.Segment, error) {
+	rows, err := s.db.Query("SELECT data FROM segments ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []*model.Segment
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		segment := &model.Segment{}
+		if err := json.Unmarshal([]byte(data), segment); err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+	return segments, rows.Err()
+}
+
+func (s *segmentStore) Upsert(segment *model.Segment) error {
+	data, err := jsonColumn(segment)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.dialect.rebind(`
+		INSERT INTO segments (name, data) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET data = excluded.data`),
+		segment.Name, data)
+	return err
+}
+
+func (s *segmentStore) Delete(segment *model.Segment) error {
+	_, err := s.db.Exec(s.dialect.rebind("DELETE FROM segments WHERE name = ?"), segment.Name)
+	return err
+}
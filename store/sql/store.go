@@ -0,0 +1,71 @@
+package sql
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/robzienert/http-healthcheck"
+	"github.com/robzienert/lever/store"
+)
+
+// StoreSpec defines the arguments for creating a new SQL Store.
+type StoreSpec struct {
+	Dialect string
+	DSN     string
+}
+
+// StoreResponse encapsulates all resulting objects from a SQL Store load.
+//
+// The database is returned so that its Closer impl can be deferred from the
+// main method.
+type StoreResponse struct {
+	Store          store.Store
+	DB             *sql.DB
+	HealthProvider healthcheck.Provider
+}
+
+// Load a new SQL storage backend, applying any migrations the database does
+// not have yet.
+func Load(spec StoreSpec) (*StoreResponse, error) {
+	d, err := GetDialect(spec.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(d.Name, spec.DSN)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(d.MaxOpenConns)
+
+	if err := RunMigrations(db, d, migrations); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &StoreResponse{
+		Store:          New(db, d),
+		DB:             db,
+		HealthProvider: NewHealthProvider(db),
+	}, nil
+}
+
+// New will initialize a new SQL storage repository. The database must already
+// have been migrated.
+func New(db *sql.DB, d *Dialect) store.Store {
+	return store.New(
+		"sql",
+		&breadcrumbStore{db: db, dialect: d},
+		&featureStore{db: db, dialect: d},
+		&segmentStore{db: db, dialect: d},
+		&usageStore{db: db, dialect: d},
+	)
+}
+
+// jsonColumn returns the value as a JSON string.
+func jsonColumn(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
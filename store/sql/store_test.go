@@ -0,0 +1,191 @@
+package sql
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func load(t *testing.T) *StoreResponse {
+	resp, err := Load(StoreSpec{Dialect: "sqlite3", DSN: ":memory:"})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return resp
+}
+
+func TestRebind(t *testing.T) {
+	cases := []struct {
+		dialect  *Dialect
+		query    string
+		expected string
+	}{
+		{Postgres, "SELECT 1", "SELECT 1"},
+		{Postgres, "a = ? AND b = ?", "a = $1 AND b = $2"},
+		{SQLite, "a = ? AND b = ?", "a = ? AND b = ?"},
+	}
+	for i, c := range cases {
+		assert.Equal(t, c.expected, c.dialect.rebind(c.query), fmt.Sprintf("case %d", i+1))
+	}
+}
+
+func TestRunMigrations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lever-sql")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	spec := StoreSpec{Dialect: "sqlite3", DSN: filepath.Join(dir, "lever.db")}
+
+	resp, err := Load(spec)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Store.Features().Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{}}))
+	resp.DB.Close()
+
+	resp, err = Load(spec)
+	assert.NoError(t, err, "migrations were applied twice")
+	defer resp.DB.Close()
+	f, err := resp.Store.Features().Get("foo")
+	assert.NoError(t, err)
+	assert.NotNil(t, f)
+
+	_, err = Load(StoreSpec{Dialect: "oracle"})
+	assert.Error(t, err)
+}
+
+func TestFeatureStore(t *testing.T) {
+	resp := load(t)
+	defer resp.DB.Close()
+	s := resp.Store.Features()
+
+	global := &model.Feature{Key: "foo", Type: "java.lang.Boolean", Gate: &model.Gate{Value: "true", Actors: []string{"a"}}}
+	namespaced := &model.Feature{Namespace: "api", Key: "foo", Gate: &model.Gate{Value: "false"}}
+	assert.NoError(t, s.Upsert(global))
+	assert.NoError(t, s.Upsert(namespaced))
+	assert.EqualValues(t, 1, global.Revision)
+
+	f, err := s.Get("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, global, f)
+	f, err = s.GetByNamespace("api", "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "false", f.Gate.Value)
+	f, err = s.GetByNamespace("api", "bar")
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+
+	list, err := s.GetList()
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	list, err = s.GetListByNamespace("api")
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	list, err = s.GetAll()
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	assert.NoError(t, s.Delete(namespaced))
+	f, err = s.GetByNamespace("api", "foo")
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+}
+
+func TestFeatureStore_Revisions(t *testing.T) {
+	resp := load(t)
+	defer resp.DB.Close()
+	s := resp.Store.Features()
+
+	assert.NoError(t, s.Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{}}))
+	first, _ := s.Get("foo")
+	second, _ := s.Get("foo")
+
+	assert.NoError(t, s.Upsert(first))
+	assert.EqualValues(t, 2, first.Revision)
+	assert.Equal(t, store.ErrConflict, s.Upsert(second), "stale upsert")
+	assert.Equal(t, store.ErrConflict, s.Upsert(&model.Feature{Key: "foo", Gate: &model.Gate{}}), "create over existing")
+	assert.EqualValues(t, 1, second.Revision, "failed upsert changed revision")
+
+	assert.Equal(t, store.ErrConflict, s.Delete(second), "stale delete")
+	assert.Equal(t, store.ErrConflict, s.Delete(&model.Feature{Key: "foo"}), "delete of unsaved feature")
+	assert.NoError(t, s.Delete(first))
+	assert.Equal(t, store.ErrConflict, s.Delete(first), "delete of deleted feature")
+	assert.NoError(t, s.Delete(&model.Feature{Key: "foo"}))
+}
+
+func TestBreadcrumbStore(t *testing.T) {
+	resp := load(t)
+	defer resp.DB.Close()
+	s := resp.Store.Breadcrumbs()
+
+	now := time.Now().UTC()
+	for i, action := range []string{"first", "second"} {
+		b := model.NewBreadcrumb(action, "me")
+		b.DateCreated = now.Add(time.Duration(i) * time.Second)
+		b.Fields["key"] = "foo"
+		assert.NoError(t, s.Create(b))
+	}
+
+	breadcrumbs, err := s.GetList()
+	assert.NoError(t, err)
+	if assert.Len(t, breadcrumbs, 2) {
+		assert.Equal(t, "first", breadcrumbs[0].Action)
+		assert.Equal(t, "me", breadcrumbs[0].Actor)
+		assert.Equal(t, "foo", breadcrumbs[0].Fields["key"])
+		assert.True(t, now.Equal(breadcrumbs[0].DateCreated))
+		assert.Equal(t, "second", breadcrumbs[1].Action)
+	}
+}
+
+func TestSegmentStore(t *testing.T) {
+	resp := load(t)
+	defer resp.DB.Close()
+	s := resp.Store.Segments()
+
+	assert.NoError(t, s.Upsert(&model.Segment{Name: "beta", Actors: []string{"a"}}))
+	assert.NoError(t, s.Upsert(&model.Segment{Name: "beta", Actors: []string{"b"}}))
+	assert.NoError(t, s.Upsert(&model.Segment{Name: "alpha"}))
+
+	segment, err := s.Get("beta")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b"}, segment.Actors)
+
+	segments, err := s.GetList()
+	assert.NoError(t, err)
+	if assert.Len(t, segments, 2) {
+		assert.Equal(t, "alpha", segments[0].Name)
+	}
+
+	assert.NoError(t, s.Delete(segment))
+	segment, err = s.Get("beta")
+	assert.NoError(t, err)
+	assert.Nil(t, segment)
+}
+
+func TestUsageStore(t *testing.T) {
+	resp := load(t)
+	defer resp.DB.Close()
+	s := resp.Store.Usage()
+
+	now := time.Now().UTC()
+	usage := func(count int64, lastEvaluated time.Time) []*model.Usage {
+		return []*model.Usage{{Namespace: "api", Key: "foo", ClientID: "app", Outcome: model.EnabledOutcome, Count: count, LastEvaluated: lastEvaluated}}
+	}
+	failed, err := s.Add(usage(2, now))
+	assert.NoError(t, err)
+	assert.Empty(t, failed)
+	_, err = s.Add(usage(3, now.Add(-time.Hour)))
+	assert.NoError(t, err)
+
+	all, err := s.GetList()
+	assert.NoError(t, err)
+	if assert.Len(t, all, 1) {
+		assert.EqualValues(t, 5, all[0].Count)
+		assert.True(t, now.Equal(all[0].LastEvaluated), "older evaluation replaced the latest")
+		assert.Equal(t, "api", all[0].Namespace)
+	}
+}
@@ -0,0 +1,63 @@
+package sql
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/robzienert/lever/model"
+)
+
+type usageStore struct {
+	db      *sql.DB
+	dialect *Dialect
+}
+
+func (s *usageStore) GetList() ([]*model.Usage, error) {
+	rows, err := s.db.Query("SELECT namespace, key, client_id, outcome, count, last_evaluated FROM feature_usage")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var all []*model.Usage
+	for rows.Next() {
+		u := &model.Usage{}
+		var lastEvaluated time.Time
+		if err := rows.Scan(&u.Namespace, &u.Key, &u.ClientID, &u.Outcome, &u.Count, &lastEvaluated); err != nil {
+			return nil, err
+		}
+		u.LastEvaluated = lastEvaluated.UTC()
+		all = append(all, u)
+	}
+	return all, rows.Err()
+}
+
+// Add increments the counts in a single transaction, so either all or none of
+// the usage is added. Times are always stored in UTC, so that SQLite can compare
+// them as strings.
+func (s *usageStore) Add(usage []*model.Usage) ([]*model.Usage, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return usage, err
+	}
+	query := s.dialect.rebind(`
+		INSERT INTO feature_usage (namespace, key, client_id, outcome, count, last_evaluated)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (namespace, key, client_id, outcome) DO UPDATE SET
+			count = feature_usage.count + excluded.count,
+			last_evaluated = CASE
+				WHEN excluded.last_evaluated > feature_usage.last_evaluated THEN excluded.last_evaluated
+				ELSE feature_usage.last_evaluated
+			END`)
+	for _, u := range usage {
+		_, err := tx.Exec(query, u.Namespace, u.Key, u.ClientID, u.Outcome, u.Count, u.LastEvaluated.UTC())
+		if err != nil {
+			tx.Rollback()
+			return usage, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return usage, err
+	}
+	return nil, nil
+}
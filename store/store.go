@@ -5,19 +5,25 @@ type Store interface {
 	Name() string
 	Breadcrumbs() BreadcrumbStore
 	Features() FeatureStore
+	Segments() SegmentStore
+	Usage() UsageStore
 }
 
 type store struct {
 	name        string
 	breadcrumbs BreadcrumbStore
 	features    FeatureStore
+	segments    SegmentStore
+	usage       UsageStore
 }
 
 func (s *store) Name() string                 { return s.name }
 func (s *store) Breadcrumbs() BreadcrumbStore { return s.breadcrumbs }
 func (s *store) Features() FeatureStore       { return s.features }
+func (s *store) Segments() SegmentStore       { return s.segments }
+func (s *store) Usage() UsageStore            { return s.usage }
 
 // New will create a new Store with the provided concrete backends.
-func New(name string, breadcrumbs BreadcrumbStore, features FeatureStore) Store {
-	return &store{name, breadcrumbs, features}
+func New(name string, breadcrumbs BreadcrumbStore, features FeatureStore, segments SegmentStore, usage UsageStore) Store {
+	return &store{name, breadcrumbs, features, segments, usage}
 }
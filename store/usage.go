@@ -0,0 +1,27 @@
+package store
+
+import (
+	"github.com/robzienert/lever/model"
+	"golang.org/x/net/context"
+)
+
+// UsageStore is the repository for interacting with evaluation usage backends.
+type UsageStore interface {
+	GetList() ([]*model.Usage, error)
+	// Add increments the stored counts of each usage by its count, and keeps
+	// the latest evaluation time. When it fails, it returns the usage that was
+	// not added, so that it can be retried without counting the rest twice.
+	Add([]*model.Usage) ([]*model.Usage, error)
+}
+
+// GetUsageList will proxy to the net.Context's usage storage backend to get
+// the usage of all features.
+func GetUsageList(c context.Context) ([]*model.Usage, error) {
+	return FromContext(c).Usage().GetList()
+}
+
+// AddUsage will proxy to the net.Context's usage storage backend to add to the
+// stored usage.
+func AddUsage(c context.Context, usage []*model.Usage) ([]*model.Usage, error) {
+	return FromContext(c).Usage().Add(usage)
+}
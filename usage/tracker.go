@@ -0,0 +1,130 @@
+package usage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+	"golang.org/x/net/context"
+)
+
+// Key represents the context value for the usage tracker.
+const Key = "usage"
+
+// FromContext returns the usage tracker from net.Context, or nil if usage is
+// not tracked.
+func FromContext(c context.Context) *Tracker {
+	v := c.Value(Key)
+	if v != nil {
+		return v.(*Tracker)
+	}
+	return nil
+}
+
+type usageKey struct {
+	namespace string
+	key       string
+	clientID  string
+	outcome   string
+}
+
+// Tracker counts feature evaluations in memory and periodically adds them to
+// the store, keeping storage writes off the evaluation path.
+//
+// Every server runs its own tracker. Counts that have not been flushed yet are
+// lost if the server stops without closing its tracker.
+type Tracker struct {
+	ctx      context.Context
+	interval time.Duration
+	clock    func() time.Time
+	lock     sync.Mutex
+	pending  map[usageKey]*model.Usage
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// NewTracker creates a tracker that flushes its counts to the store every
+// interval.
+func NewTracker(s store.Store, interval time.Duration) *Tracker {
+	return &Tracker{
+		ctx:      context.WithValue(context.Background(), store.Key, s),
+		interval: interval,
+		clock:    time.Now,
+		pending:  make(map[usageKey]*model.Usage),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Track counts an evaluation of the feature by the OAuth client.
+func (t *Tracker) Track(f *model.Feature, clientID string, outcome string) {
+	now := t.clock().UTC()
+	k := usageKey{f.Namespace, f.Key, clientID, outcome}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	u, ok := t.pending[k]
+	if !ok {
+		u = &model.Usage{Namespace: f.Namespace, Key: f.Key, ClientID: clientID, Outcome: outcome}
+		t.pending[k] = u
+	}
+	u.Count++
+	u.LastEvaluated = now
+}
+
+// Start flushes the tracker in the background until it is closed.
+func (t *Tracker) Start() {
+	go func() {
+		defer close(t.stopped)
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.Flush()
+			case <-t.done:
+				t.Flush()
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the tracker after flushing the remaining counts.
+func (t *Tracker) Close() {
+	close(t.done)
+	<-t.stopped
+}
+
+// Flush adds the counts since the last flush to the store. Counts that the store
+// could not add are kept for the next flush, while those it added are not.
+func (t *Tracker) Flush() {
+	t.lock.Lock()
+	pending := t.pending
+	t.pending = make(map[usageKey]*model.Usage)
+	t.lock.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	usage := make([]*model.Usage, 0, len(pending))
+	for _, u := range pending {
+		usage = append(usage, u)
+	}
+	failed, err := store.AddUsage(t.ctx, usage)
+	if err != nil {
+		logrus.WithField("err", err).Error("Could not store feature usage")
+
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		for _, u := range failed {
+			k := usageKey{u.Namespace, u.Key, u.ClientID, u.Outcome}
+			if newer, ok := t.pending[k]; ok {
+				u.Add(newer)
+			}
+			t.pending[k] = u
+		}
+	}
+}
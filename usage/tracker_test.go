@@ -0,0 +1,115 @@
+package usage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/robzienert/lever/model"
+	"github.com/robzienert/lever/store"
+	"github.com/robzienert/lever/store/memory"
+	"github.com/robzienert/lever/store/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingUsageStore adds the first usage of each batch, up to its limit, and
+// fails to add the rest.
+type failingUsageStore struct {
+	store.UsageStore
+	limit int
+}
+
+func (s *failingUsageStore) Add(usage []*model.Usage) ([]*model.Usage, error) {
+	n := s.limit
+	if n > len(usage) {
+		n = len(usage)
+	}
+	if _, err := s.UsageStore.Add(usage[:n]); err != nil {
+		return usage, err
+	}
+	return usage[n:], errors.New("unavailable")
+}
+
+func TestTracker_Flush(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	foo := &model.Feature{Namespace: "mobile", Key: "foo"}
+	bar := &model.Feature{Key: "bar"}
+
+	memStore := memory.Load()
+	tracker := NewTracker(memStore, time.Minute)
+	tracker.clock = func() time.Time { return now }
+	tracker.Track(foo, "web", model.EnabledOutcome)
+	tracker.Track(foo, "web", model.EnabledOutcome)
+	tracker.Track(foo, "ios", "treatment")
+	tracker.Flush()
+
+	tracker.clock = func() time.Time { return now.Add(time.Hour) }
+	tracker.Track(foo, "web", model.EnabledOutcome)
+	tracker.Track(bar, "web", model.DisabledOutcome)
+	tracker.Flush()
+
+	usage, err := memStore.Usage().GetList()
+	assert.NoError(t, err)
+	byOutcome := make(map[string]*model.Usage)
+	for _, u := range usage {
+		byOutcome[u.ID()+":"+u.ClientID+":"+u.Outcome] = u
+	}
+	assert.Len(t, byOutcome, 3)
+	assert.Equal(t, &model.Usage{Namespace: "mobile", Key: "foo", ClientID: "web", Outcome: model.EnabledOutcome, Count: 3, LastEvaluated: now.Add(time.Hour)}, byOutcome["mobile/foo:web:enabled"])
+	assert.Equal(t, int64(1), byOutcome["mobile/foo:ios:treatment"].Count)
+	assert.Equal(t, now, byOutcome["mobile/foo:ios:treatment"].LastEvaluated)
+	assert.Equal(t, int64(1), byOutcome["bar:web:disabled"].Count)
+}
+
+func TestTracker_FlushError(t *testing.T) {
+	foo := &model.Feature{Key: "foo"}
+	tracker := NewTracker(store.New("failing", nil, nil, nil, &failingUsageStore{UsageStore: &mock.UsageStore{}}), time.Minute)
+	tracker.Track(foo, "web", model.EnabledOutcome)
+	tracker.Flush()
+	tracker.Track(foo, "web", model.EnabledOutcome)
+
+	assert.Len(t, tracker.pending, 1)
+	for _, u := range tracker.pending {
+		assert.Equal(t, int64(2), u.Count)
+	}
+}
+
+func TestTracker_FlushPartialError(t *testing.T) {
+	memStore := memory.Load()
+	tracker := NewTracker(store.New("failing", nil, nil, nil, &failingUsageStore{UsageStore: memStore.Usage(), limit: 1}), time.Minute)
+	tracker.Track(&model.Feature{Key: "foo"}, "web", model.EnabledOutcome)
+	tracker.Track(&model.Feature{Key: "foo"}, "web", model.EnabledOutcome)
+	tracker.Track(&model.Feature{Key: "bar"}, "web", model.EnabledOutcome)
+	tracker.Flush()
+
+	stored, err := memStore.Usage().GetList()
+	assert.NoError(t, err)
+	if assert.Len(t, stored, 1) && assert.Len(t, tracker.pending, 1) {
+		for _, u := range tracker.pending {
+			assert.NotEqual(t, stored[0].Key, u.Key, "added usage is kept for the next flush")
+		}
+	}
+
+	// Only the usage that failed is added by the next flush.
+	tracker.Flush()
+	stored, err = memStore.Usage().GetList()
+	assert.NoError(t, err)
+	assert.Len(t, tracker.pending, 0)
+	counts := make(map[string]int64)
+	for _, u := range stored {
+		counts[u.Key] = u.Count
+	}
+	assert.Equal(t, map[string]int64{"foo": 2, "bar": 1}, counts)
+}
+
+func TestTracker_Close(t *testing.T) {
+	memStore := memory.Load()
+	tracker := NewTracker(memStore, time.Hour)
+	tracker.Start()
+	tracker.Track(&model.Feature{Key: "foo"}, "web", model.EnabledOutcome)
+	tracker.Close()
+
+	usage, err := memStore.Usage().GetList()
+	assert.NoError(t, err)
+	assert.Len(t, usage, 1)
+}